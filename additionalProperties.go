@@ -15,9 +15,10 @@ import (
 // This function ensures that all properties not explicitly mentioned or matched are validated according to a default schema or constraints.
 //
 // Reference: https://json-schema.org/draft/2020-12/json-schema-core#name-additionalproperties
-func evaluateAdditionalProperties(schema *Schema, object map[string]interface{}, evaluatedProps map[string]bool, evaluatedItems map[int]bool, dynamicScope *DynamicScope) ([]*EvaluationResult, *EvaluationError) {
+func evaluateAdditionalProperties(schema *Schema, object map[string]interface{}, result *EvaluationResult, evaluatedProps map[string]bool, evaluatedItems map[int]bool, dynamicScope *DynamicScope) ([]*EvaluationResult, *EvaluationError) {
 	results := []*EvaluationResult{}
 	invalid_properties := []string{}
+	matched := []string{}
 
 	properties := make(map[string]bool)
 	if schema.Properties != nil {
@@ -38,24 +39,29 @@ func evaluateAdditionalProperties(schema *Schema, object map[string]interface{},
 	// Evaluate additional properties
 	if schema.AdditionalProperties != nil {
 		for propName, propValue := range object {
+			if dynamicScope.stops(len(invalid_properties) > 0) {
+				break
+			}
 			if !properties[propName] {
-				result, _, _ := schema.AdditionalProperties.evaluate(propValue, dynamicScope)
-				if result != nil {
-					result.SetEvaluationPath(fmt.Sprintf("/additionalProperties/%s", propName)).
-						SetSchemaLocation(schema.GetSchemaLocation(fmt.Sprintf("/additionalProperties/%s", propName))).
-						SetInstanceLocation(fmt.Sprintf("/%s", propName))
-
-					results = append(results, result)
-					if !result.IsValid() {
+				if dynamicScope.annotates() {
+					matched = append(matched, propName)
+				}
+				propResult, _, _ := schema.AdditionalProperties.evaluateAt(propValue, "/additionalProperties", propName, dynamicScope)
+				if propResult != nil {
+					results = append(results, propResult)
+					if !propResult.IsValid() {
 						invalid_properties = append(invalid_properties, propName)
 					}
 				}
 
 				// Mark property as evaluated
-				evaluatedProps[propName] = true
+				if evaluatedProps != nil {
+					evaluatedProps[propName] = true
+				}
 			}
 		}
 	}
+	annotateNames(result, "additionalProperties", matched)
 
 	if len(invalid_properties) == 1 {
 		return results, NewEvaluationError("additionalProperties", "additional_property_mismatch", "Additional property {property} does not match the schema", map[string]interface{}{
@@ -1,7 +1,6 @@
 package jsonschema
 
 import (
-	"fmt"
 	"strconv"
 	"strings"
 )
@@ -24,6 +23,9 @@ func evaluateAllOf(schema *Schema, instance interface{}, evaluatedProps map[stri
 	results := []*EvaluationResult{}
 
 	for i, subSchema := range schema.AllOf {
+		if dynamicScope.stops(len(invalid_indexs) > 0) {
+			break // The remaining subschemas cannot make the instance valid again.
+		}
 		if subSchema != nil {
 			skipEval := false
 			if subSchema.Boolean != nil && *subSchema.Boolean {
@@ -31,17 +33,14 @@ func evaluateAllOf(schema *Schema, instance interface{}, evaluatedProps map[stri
 				skipEval = true
 			}
 
-			result, schemaEvaluatedProps, schemaEvaluatedItems := subSchema.evaluate(instance, dynamicScope)
+			result, schemaEvaluatedProps, schemaEvaluatedItems := subSchema.evaluateIn(instance, "/allOf/"+strconv.Itoa(i), dynamicScope)
 			if !skipEval {
 				mergeStringMaps(evaluatedProps, schemaEvaluatedProps)
 				mergeIntMaps(evaluatedItems, schemaEvaluatedItems)
 			}
 
 			if result != nil {
-				results = append(results, result.SetEvaluationPath(fmt.Sprintf("/allOf/%d", i)).
-					SetSchemaLocation(schema.GetSchemaLocation(fmt.Sprintf("/allOf/%d", i))).
-					SetInstanceLocation(""),
-				)
+				results = append(results, result)
 
 				if !result.IsValid() {
 					invalid_indexs = append(invalid_indexs, strconv.Itoa(i))
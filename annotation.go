@@ -0,0 +1,68 @@
+package jsonschema
+
+import "sort"
+
+// Annotation is the value a keyword attached to a location of the instance, such as the "title" of the
+// schema it conforms to or the names of the properties matched by "properties".
+type Annotation struct {
+	InstancePath string      `json:"instancePath"` // JSON Pointer of the annotated value.
+	SchemaPath   string      `json:"schemaPath"`   // Evaluation path of the schema holding the keyword.
+	Keyword      string      `json:"keyword"`      // Keyword that produced the annotation.
+	Value        interface{} `json:"value"`        // Value of the annotation.
+}
+
+// AnnotationsAt returns the annotations collected for the value found at the JSON Pointer instancePath,
+// such as "" for the instance itself or "/address/street". The annotations of the schemas that failed,
+// and of the subschemas they applied, are left out, as the specification requires. Annotations are
+// returned in evaluation order, sorted by keyword within a schema, and none are collected in fail-fast
+// mode.
+func (e *EvaluationResult) AnnotationsAt(instancePath string) []Annotation {
+	var annotations []Annotation
+	walkAnnotations(e, func(result *EvaluationResult) {
+		if result.InstanceLocation != instancePath || len(result.Annotations) == 0 {
+			return
+		}
+
+		keywords := make([]string, 0, len(result.Annotations))
+		for keyword := range result.Annotations {
+			keywords = append(keywords, keyword)
+		}
+		sort.Strings(keywords)
+
+		for _, keyword := range keywords {
+			annotations = append(annotations, Annotation{
+				InstancePath: result.InstanceLocation,
+				SchemaPath:   result.EvaluationPath,
+				Keyword:      keyword,
+				Value:        result.Annotations[keyword],
+			})
+		}
+	})
+	return annotations
+}
+
+// walkAnnotations calls fn with result and with the results of its subschemas, depth first, skipping the
+// results that failed along with their subschemas.
+func walkAnnotations(result *EvaluationResult, fn func(result *EvaluationResult)) {
+	if result == nil || !result.IsValid() {
+		return
+	}
+
+	result.locate()
+	fn(result)
+	for _, detail := range result.Details {
+		walkAnnotations(detail, fn)
+	}
+}
+
+// annotateNames adds the annotation of an applicator keyword of objects to result: the names of the
+// properties of the instance the keyword applied its subschemas to, in sorted order. Nothing is added
+// if the keyword applied to no property.
+func annotateNames(result *EvaluationResult, keyword string, names []string) {
+	if len(names) == 0 {
+		return
+	}
+
+	sort.Strings(names)
+	result.AddAnnotation(keyword, names)
+}
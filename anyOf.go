@@ -1,8 +1,6 @@
 package jsonschema
 
-import (
-	"fmt"
-)
+import "strconv"
 
 // EvaluateAnyOf checks if the data conforms to at least one of the schemas specified in the anyOf attribute.
 // According to the JSON Schema Draft 2020-12:
@@ -28,13 +26,10 @@ func evaluateAnyOf(schema *Schema, data interface{}, evaluatedProps map[string]b
 				// If the schema is `true`, skip updating evaluated properties and items.
 				skipEval = true
 			}
-			result, schemaEvaluatedProps, schemaEvaluatedItems := subSchema.evaluate(data, dynamicScope)
+			result, schemaEvaluatedProps, schemaEvaluatedItems := subSchema.evaluateIn(data, "/anyOf/"+strconv.Itoa(i), dynamicScope)
 
 			if result != nil {
-				results = append(results, result.SetEvaluationPath(fmt.Sprintf("/anyOf/%d", i)).
-					SetSchemaLocation(schema.GetSchemaLocation(fmt.Sprintf("/anyOf/%d", i))).
-					SetInstanceLocation(""),
-				)
+				results = append(results, result)
 
 				if result.IsValid() {
 					valid = true
@@ -6,6 +6,9 @@ import (
 	"encoding/xml"
 	"io"
 	"net/http"
+	"sort"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/goccy/go-json"
@@ -14,24 +17,51 @@ import (
 )
 
 // Compiler is a structure that manages schema compilation and validation.
+// A Compiler is safe for concurrent use: the schema cache and the handler registries
+// are guarded by an internal lock, so it can be shared across goroutines once configured.
+// The exported registries should only be modified through the Register* methods.
 type Compiler struct {
-	schemas        map[string]*Schema                                 // Cache of compiled schemas.
-	Decoders       map[string]func(string) ([]byte, error)            // Decoders for various encoding formats.
-	MediaTypes     map[string]func([]byte) (interface{}, error)       // Media type handlers for unmarshalling data.
-	Loaders        map[string]func(url string) (io.ReadCloser, error) // Functions to load schemas from URLs.
-	DefaultBaseURI string                                             // Base URI used to resolve relative references.
-	AssertFormat   bool                                               // Flag to enforce format validation.
+	mu              sync.RWMutex                                       // Guards schemas and the handler registries.
+	schemas         map[string]*Schema                                 // Cache of compiled schemas.
+	regexps         *regexCache                                        // Bounded cache of compiled regular expressions.
+	vocabularies    map[string]bool                                    // Vocabularies understood by the compiler.
+	keywords        map[string]Keyword                                 // Custom keywords, by name.
+	formats         map[string]func(interface{}) bool                  // Format validators registered on the compiler.
+	Decoders        map[string]func(string) ([]byte, error)            // Decoders for various encoding formats.
+	MediaTypes      map[string]func([]byte) (interface{}, error)       // Media type handlers for unmarshalling data.
+	loaders         map[string]LoaderFunc                              // Context-aware loaders, see RegisterLoaderContext.
+	Loaders         map[string]func(url string) (io.ReadCloser, error) // Functions to load schemas from URLs.
+	DefaultBaseURI  string                                             // Base URI used to resolve relative references.
+	AssertFormat    bool                                               // Flag to enforce format validation.
+	UnknownFormats  UnknownFormatPolicy                                // Treatment of formats without a validator.
+	StrictRefs      bool                                               // Flag to fail compilation on unresolvable references.
+	ValidateSchemas bool                                               // Flag to check schemas against the meta-schema before compiling them.
 }
 
+// LoaderFunc loads the resource identified by url. Implementations should stop and return
+// the context error as soon as ctx is canceled or its deadline expires.
+type LoaderFunc func(ctx context.Context, url string) (io.ReadCloser, error)
+
+// defaultLoaderTimeout bounds network loads whose context carries no deadline.
+const defaultLoaderTimeout = 10 * time.Second
+
 // NewCompiler creates a new Compiler instance and initializes it with default settings.
 func NewCompiler() *Compiler {
 	compiler := &Compiler{
-		schemas:        make(map[string]*Schema),
-		Decoders:       make(map[string]func(string) ([]byte, error)),
-		MediaTypes:     make(map[string]func([]byte) (interface{}, error)),
-		Loaders:        make(map[string]func(url string) (io.ReadCloser, error)),
-		DefaultBaseURI: "",
-		AssertFormat:   false,
+		schemas:         make(map[string]*Schema),
+		vocabularies:    make(map[string]bool),
+		keywords:        make(map[string]Keyword),
+		formats:         make(map[string]func(interface{}) bool),
+		regexps:         newRegexCache(RE2RegexEngine{}, defaultRegexCacheSize),
+		Decoders:        make(map[string]func(string) ([]byte, error)),
+		MediaTypes:      make(map[string]func([]byte) (interface{}, error)),
+		loaders:         make(map[string]LoaderFunc),
+		Loaders:         make(map[string]func(url string) (io.ReadCloser, error)),
+		DefaultBaseURI:  "",
+		AssertFormat:    false,
+		UnknownFormats:  UnknownFormatAnnotate,
+		StrictRefs:      false,
+		ValidateSchemas: false,
 	}
 	compiler.initDefaults()
 	return compiler
@@ -39,6 +69,18 @@ func NewCompiler() *Compiler {
 
 // Compile compiles a JSON schema and caches it. If an URI is provided, it uses that as the key; otherwise, it generates a hash.
 func (c *Compiler) Compile(jsonSchema []byte, uris ...string) (*Schema, error) {
+	return c.CompileContext(context.Background(), jsonSchema, uris...)
+}
+
+// CompileContext is like Compile, but remote references are loaded with the given context,
+// so compilation stops once ctx is canceled or its deadline expires.
+func (c *Compiler) CompileContext(ctx context.Context, jsonSchema []byte, uris ...string) (*Schema, error) {
+	if c.ValidateSchemas {
+		if err := c.checkSchema(jsonSchema, uris...); err != nil {
+			return nil, err
+		}
+	}
+
 	schema, err := newSchema(jsonSchema)
 	if err != nil {
 		return nil, err
@@ -52,50 +94,131 @@ func (c *Compiler) Compile(jsonSchema []byte, uris ...string) (*Schema, error) {
 	if uri != "" && isValidURI(uri) {
 		schema.uri = uri
 
-		if existingSchema, exists := c.schemas[uri]; exists {
+		if existingSchema, exists := c.lookupSchema(uri); exists {
 			return existingSchema, nil
 		}
 	}
 
-	schema.initializeSchema(c, nil)
+	vocabularies, metaSchemaErr, err := c.resolveVocabularies(ctx, schema)
+	if err != nil {
+		return nil, err
+	}
+	schema.vocabularies = vocabularies
+
+	if err := schema.initializeSchema(ctx, c, nil); err != nil {
+		return nil, err
+	}
+
+	refErrs := schema.resolveReferences(ctx)
+	if metaSchemaErr != nil {
+		refErrs = append(refErrs, metaSchemaErr)
+	}
+	if len(refErrs) > 0 {
+		sort.SliceStable(refErrs, func(i, j int) bool {
+			return refErrs[i].Location < refErrs[j].Location
+		})
+		if c.StrictRefs {
+			return nil, &CompileError{URI: schema.uri, References: refErrs}
+		}
+		schema.warnings = refErrs
+	}
 
 	if schema.uri != "" && isValidURI(schema.uri) {
-		c.SetSchema(schema.uri, schema)
+		return c.storeSchema(schema.uri, schema), nil
 	}
 
 	return schema, nil
 }
 
-// resolveSchemaURL attempts to fetch and compile a schema from a URL.
-func (c *Compiler) resolveSchemaURL(url string) (*Schema, error) {
-	id, anchor := splitRef(url)
-	if schema, exists := c.schemas[id]; exists {
-		return schema, nil // Return cached schema if available
+// CompileError is returned by Compile in strict mode when a schema contains references that
+// cannot be resolved. It lists every unresolved $ref and $dynamicRef, ordered by location.
+type CompileError struct {
+	URI        string            // URI of the compiled schema, if any.
+	References []*ReferenceError // The unresolved references.
+}
+
+// Error returns a description of all unresolved references.
+func (e *CompileError) Error() string {
+	messages := make([]string, len(e.References))
+	for i, ref := range e.References {
+		messages[i] = ref.Error()
 	}
 
-	loader, ok := c.Loaders[getURLScheme(url)]
-	if !ok {
-		return nil, ErrNoLoaderRegistered
+	prefix := "failed to compile schema"
+	if e.URI != "" {
+		prefix += " " + e.URI
+	}
+	return prefix + ": " + strings.Join(messages, "; ")
+}
+
+// Unwrap returns the unresolved references, so they can be matched with errors.Is and errors.As.
+func (e *CompileError) Unwrap() []error {
+	errs := make([]error, len(e.References))
+	for i, ref := range e.References {
+		errs[i] = ref
 	}
+	return errs
+}
 
-	body, err := loader(url)
+// checkSchema validates a schema document against the meta-schema, returning an *InvalidSchemaError
+// when it does not conform.
+func (c *Compiler) checkSchema(jsonSchema []byte, uris ...string) error {
+	result, err := c.ValidateSchema(jsonSchema)
 	if err != nil {
-		return nil, err
+		return err
+	}
+	if result.IsValid() {
+		return nil
 	}
-	defer body.Close() //nolint:errcheck
 
-	data, err := io.ReadAll(body)
+	invalid := &InvalidSchemaError{Result: result}
+	if len(uris) > 0 {
+		invalid.URI = uris[0]
+	}
+	return invalid
+}
+
+// lookupSchema returns the cached schema for the given URI, if any.
+func (c *Compiler) lookupSchema(uri string) (*Schema, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	schema, exists := c.schemas[uri]
+	return schema, exists
+}
+
+// storeSchema caches the schema under the given URI unless another goroutine has already
+// compiled the same URI, in which case the previously cached schema is returned.
+func (c *Compiler) storeSchema(uri string, schema *Schema) *Schema {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if existingSchema, exists := c.schemas[uri]; exists {
+		return existingSchema
+	}
+	c.schemas[uri] = schema
+	return schema
+}
+
+// resolveSchemaURL attempts to fetch and compile a schema from a URL.
+func (c *Compiler) resolveSchemaURL(ctx context.Context, url string) (*Schema, error) {
+	id, anchor := splitRef(url)
+	if schema, exists := c.lookupSchema(id); exists {
+		return schema, nil // Return cached schema if available
+	}
+
+	data, err := c.loadSchemaData(ctx, url)
 	if err != nil {
-		return nil, ErrFailedToReadData
+		return nil, err
 	}
 
-	schema, err := c.Compile(data, id)
+	schema, err := c.CompileContext(ctx, data, id)
 
 	if err != nil {
 		return nil, err
 	}
 
-	c.schemas[id] = schema
+	schema = c.storeSchema(id, schema)
 
 	if anchor != "" {
 		return schema.resolveAnchor(anchor)
@@ -106,26 +229,68 @@ func (c *Compiler) resolveSchemaURL(url string) (*Schema, error) {
 
 // SetSchema associates a specific schema with a URI.
 func (c *Compiler) SetSchema(uri string, schema *Schema) *Compiler {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
 	c.schemas[uri] = schema
 	return c
 }
 
+// GetSchemas returns a snapshot of all cached schemas keyed by URI.
 func (c *Compiler) GetSchemas() map[string]*Schema {
-	return c.schemas
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	schemas := make(map[string]*Schema, len(c.schemas))
+	for uri, schema := range c.schemas {
+		schemas[uri] = schema
+	}
+	return schemas
+}
+
+// loadSchemaData reads the schema document published at url. The official meta-schemas are
+// served from their embedded copies, so they resolve in every compiler without a network round
+// trip; any other document is fetched with the loader registered for the URL scheme.
+func (c *Compiler) loadSchemaData(ctx context.Context, url string) ([]byte, error) {
+	if data, ok := embeddedMetaSchema(url); ok {
+		return data, nil
+	}
+
+	loader, ok := c.getLoader(getURLScheme(url))
+	if !ok {
+		return nil, ErrNoLoaderRegistered
+	}
+
+	body, err := loader(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close() //nolint:errcheck
+
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return nil, ErrFailedToReadData
+	}
+	return data, nil
 }
 
 // GetSchema retrieves a schema by reference. If the schema is not found in the cache and the ref is a URL, it tries to resolve it.
 func (c *Compiler) GetSchema(ref string) (*Schema, error) {
+	return c.GetSchemaContext(context.Background(), ref)
+}
+
+// GetSchemaContext is like GetSchema, but a schema that has to be loaded is fetched with the given context.
+func (c *Compiler) GetSchemaContext(ctx context.Context, ref string) (*Schema, error) {
 	baseURI, anchor := splitRef(ref)
 
-	if schema, exists := c.schemas[baseURI]; exists {
+	if schema, exists := c.lookupSchema(baseURI); exists {
 		if baseURI == ref {
 			return schema, nil
 		}
 		return schema.resolveAnchor(anchor)
 	}
 
-	return c.resolveSchemaURL(ref)
+	return c.resolveSchemaURL(ctx, ref)
 }
 
 // SetDefaultBaseURI sets the default base URL for resolving relative references.
@@ -140,29 +305,172 @@ func (c *Compiler) SetAssertFormat(assert bool) *Compiler {
 	return c
 }
 
+// SetUnknownFormatPolicy selects how formats without a validator are treated: ignored, recorded
+// as an annotation (the default) or rejected. Schemas whose dialect includes the format-assertion
+// vocabulary always reject them.
+func (c *Compiler) SetUnknownFormatPolicy(policy UnknownFormatPolicy) *Compiler {
+	c.UnknownFormats = policy
+	return c
+}
+
+// SetStrictRefs enables or disables strict reference resolution. In strict mode, Compile returns a
+// *CompileError when any $ref or $dynamicRef cannot be resolved; otherwise the schema is compiled
+// and the unresolved references are reported by Schema.CompileWarnings.
+func (c *Compiler) SetStrictRefs(strict bool) *Compiler {
+	c.StrictRefs = strict
+	return c
+}
+
+// SetValidateSchemas enables or disables checking every compiled schema document, including
+// remote ones, against the bundled 2020-12 meta-schema. When enabled, Compile returns an
+// *InvalidSchemaError holding the located errors of a schema that does not conform.
+func (c *Compiler) SetValidateSchemas(validate bool) *Compiler {
+	c.ValidateSchemas = validate
+	return c
+}
+
+// SetRegexEngine selects the engine that compiles the regular expressions of the pattern and
+// patternProperties keywords and of the regex format. Schemas compiled before the call keep the
+// expressions compiled by the previous engine.
+func (c *Compiler) SetRegexEngine(engine RegexEngine) *Compiler {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.regexps = newRegexCache(engine, defaultRegexCacheSize)
+	return c
+}
+
 // RegisterDecoder adds a new decoder function for a specific encoding.
 func (c *Compiler) RegisterDecoder(encodingName string, decoderFunc func(string) ([]byte, error)) *Compiler {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
 	c.Decoders[encodingName] = decoderFunc
 	return c
 }
 
 // RegisterMediaType adds a new unmarshal function for a specific media type.
 func (c *Compiler) RegisterMediaType(mediaTypeName string, unmarshalFunc func([]byte) (interface{}, error)) *Compiler {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
 	c.MediaTypes[mediaTypeName] = unmarshalFunc
 	return c
 }
 
-// RegisterLoader adds a new loader function for a specific URI scheme.
+// RegisterLoader adds a new loader function for a specific URI scheme, replacing any loader registered
+// for it. The loader does not observe cancellation; use RegisterLoaderContext for loaders that do
+// network I/O.
 func (c *Compiler) RegisterLoader(scheme string, loaderFunc func(url string) (io.ReadCloser, error)) *Compiler {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
 	c.Loaders[scheme] = loaderFunc
+	delete(c.loaders, scheme)
+	return c
+}
+
+// RegisterLoaderContext adds a new context-aware loader function for a specific URI scheme, replacing
+// any loader registered for it.
+func (c *Compiler) RegisterLoaderContext(scheme string, loaderFunc LoaderFunc) *Compiler {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.loaders[scheme] = loaderFunc
+	delete(c.Loaders, scheme)
+	return c
+}
+
+// RegisterFormat adds a validator for the given format, taking precedence over the built-in
+// validators and the deprecated global Formats. When types are given, such as "string" or
+// "number", the validator only checks instances of those JSON types and the others are valid.
+func (c *Compiler) RegisterFormat(name string, validate func(interface{}) bool, types ...string) *Compiler {
+	if len(types) > 0 {
+		untyped := validate
+		validate = func(v interface{}) bool {
+			return !formatAppliesTo(v, types) || untyped(v)
+		}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.formats[name] = validate
 	return c
 }
 
+// getFormat returns the validator of the given format: the one registered on the compiler,
+// otherwise the one of the deprecated global Formats, otherwise the built-in one.
+func (c *Compiler) getFormat(name string) (func(interface{}) bool, bool) {
+	if c != nil {
+		c.mu.RLock()
+		validate, ok := c.formats[name]
+		c.mu.RUnlock()
+		if ok {
+			return validate, true
+		}
+
+		if name == "regex" {
+			// Regular expressions are checked with the dialect selected on the compiler.
+			return c.isRegex, true
+		}
+	}
+
+	if validate, ok := Formats[name]; ok {
+		return validate, true
+	}
+	validate, ok := builtinFormats[name]
+	return validate, ok
+}
+
+// getDecoder returns the decoder registered for the given encoding.
+func (c *Compiler) getDecoder(encodingName string) (func(string) ([]byte, error), bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	decoder, ok := c.Decoders[encodingName]
+	return decoder, ok
+}
+
+// getMediaType returns the unmarshal function registered for the given media type.
+func (c *Compiler) getMediaType(mediaTypeName string) (func([]byte) (interface{}, error), bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	unmarshal, ok := c.MediaTypes[mediaTypeName]
+	return unmarshal, ok
+}
+
+// getLoader returns the loader registered for the given URI scheme. A loader set in Loaders, which
+// does not observe cancellation, is only called if the context is not done yet, and takes precedence
+// over the context-aware loaders, such as the default HTTP ones, so that setting it directly replaces
+// them as it always did.
+func (c *Compiler) getLoader(scheme string) (LoaderFunc, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if loader, ok := c.Loaders[scheme]; ok {
+		return func(ctx context.Context, url string) (io.ReadCloser, error) {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+			return loader(url)
+		}, true
+	}
+	loader, ok := c.loaders[scheme]
+	return loader, ok
+}
+
 // initDefaults initializes default values for decoders, media types, and loaders.
 func (c *Compiler) initDefaults() {
 	c.Decoders["base64"] = base64.StdEncoding.DecodeString
+	for _, uri := range standardVocabularies {
+		c.vocabularies[uri] = true
+	}
 	c.setupMediaTypes()
 	c.setupLoaders()
+	c.keywords["x-tf-accepted-objects"] = AcceptedObjectsKeyword{}
+	c.keywords["x-tf-facets"] = FacetsKeyword{}
 }
 
 // setupMediaTypes configures default media type handlers.
@@ -194,32 +502,55 @@ func (c *Compiler) setupMediaTypes() {
 
 // setupLoaders configures default loaders for fetching schemas via HTTP/HTTPS.
 func (c *Compiler) setupLoaders() {
-	client := &http.Client{
-		Timeout: 10 * time.Second, // Set a reasonable timeout for network requests.
-	}
+	client := &http.Client{}
+
+	defaultHTTPLoader := func(ctx context.Context, url string) (io.ReadCloser, error) {
+		cancel := context.CancelFunc(func() {})
+		if _, ok := ctx.Deadline(); !ok {
+			// Set a reasonable timeout for network requests without a caller deadline.
+			ctx, cancel = context.WithTimeout(ctx, defaultLoaderTimeout)
+		}
 
-	defaultHTTPLoader := func(url string) (io.ReadCloser, error) {
-		req, err := http.NewRequestWithContext(context.Background(), "GET", url, nil)
+		req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 		if err != nil {
+			cancel()
 			return nil, err
 		}
 
 		resp, err := client.Do(req)
 		if err != nil {
+			ctxErr := ctx.Err()
+			cancel()
+			if ctxErr != nil {
+				return nil, ctxErr
+			}
 			return nil, ErrFailedToFetch
 		}
 
 		if resp.StatusCode != http.StatusOK {
 			err = resp.Body.Close()
+			cancel()
 			if err != nil {
 				return nil, err
 			}
 			return nil, ErrInvalidHTTPStatusCode
 		}
 
-		return resp.Body, nil
+		return &cancelOnClose{ReadCloser: resp.Body, cancel: cancel}, nil
 	}
 
-	c.RegisterLoader("http", defaultHTTPLoader)
-	c.RegisterLoader("https", defaultHTTPLoader)
+	c.RegisterLoaderContext("http", defaultHTTPLoader)
+	c.RegisterLoaderContext("https", defaultHTTPLoader)
+}
+
+// cancelOnClose releases the request context of a loaded body once the body is closed.
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+// Close closes the underlying body and cancels its request context.
+func (b *cancelOnClose) Close() error {
+	defer b.cancel()
+	return b.ReadCloser.Close()
 }
@@ -1,8 +1,14 @@
 package jsonschema
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"io"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 )
 
 const (
@@ -184,3 +190,249 @@ func createTestSchemaJSON(id string, properties map[string]string, required []st
 		"required": %s
 	}`, id, propsStr, reqStr)
 }
+
+func TestCompileConcurrently(t *testing.T) {
+	compiler := NewCompiler()
+
+	const workers = 16
+	var wg sync.WaitGroup
+	schemas := make([]*Schema, workers)
+
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			schemaJSON := createTestSchemaJSON("http://example.com/shared", map[string]string{"name": "string"}, []string{"name"})
+			schema, err := compiler.Compile([]byte(schemaJSON))
+			if err != nil {
+				t.Errorf("Failed to compile schema: %s", err)
+				return
+			}
+			schemas[i] = schema
+
+			otherJSON := createTestSchemaJSON(fmt.Sprintf("http://example.com/schema-%d", i), map[string]string{"age": "integer"}, nil)
+			if _, err := compiler.Compile([]byte(otherJSON)); err != nil {
+				t.Errorf("Failed to compile schema: %s", err)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	for i := 1; i < workers; i++ {
+		if schemas[i] != schemas[0] {
+			t.Fatalf("Expected every goroutine to receive the cached schema for the shared URI")
+		}
+	}
+
+	if len(compiler.GetSchemas()) != workers+1 {
+		t.Errorf("Expected %d cached schemas, found %d", workers+1, len(compiler.GetSchemas()))
+	}
+}
+
+func TestGetSchemaContextDeadline(t *testing.T) {
+	compiler := NewCompiler()
+	compiler.RegisterLoaderContext("slow", func(ctx context.Context, url string) (io.ReadCloser, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	_, err := compiler.GetSchemaContext(ctx, "slow://example.com/schema")
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Expected the deadline to stop loading, got: %v", err)
+	}
+}
+
+func TestLoadersMap(t *testing.T) {
+	compiler := NewCompiler()
+	compiler.Loaders["https"] = func(url string) (io.ReadCloser, error) {
+		return io.NopCloser(strings.NewReader(`{"$id": "` + url + `", "type": "string"}`)), nil
+	}
+
+	// A loader set directly in the map replaces the default HTTP one.
+	schema, err := compiler.GetSchema("https://example.com/string")
+	if err != nil {
+		t.Fatalf("Expected the map loader to be used, got: %v", err)
+	}
+	if !schema.Validate("a").IsValid() {
+		t.Errorf("Expected the loaded schema to accept a string")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := compiler.GetSchemaContext(ctx, "https://example.com/other"); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected a canceled context to stop loading, got: %v", err)
+	}
+
+	// Registering a context-aware loader replaces the one of the map.
+	compiler.RegisterLoaderContext("https", func(ctx context.Context, url string) (io.ReadCloser, error) {
+		return io.NopCloser(strings.NewReader(`{"$id": "` + url + `", "type": "integer"}`)), nil
+	})
+	if _, ok := compiler.Loaders["https"]; ok {
+		t.Errorf("Expected the map loader to be replaced")
+	}
+	schema, err = compiler.GetSchema("https://example.com/integer")
+	if err != nil || !schema.Validate(1).IsValid() {
+		t.Errorf("Expected the context-aware loader to be used, got: %v", err)
+	}
+}
+
+func TestCompileUnresolvedReferences(t *testing.T) {
+	schemaJSON := []byte(`{
+		"$id": "http://example.com/root",
+		"properties": {
+			"a": {"$ref": "#/$defs/missing"},
+			"b": {"$ref": "#/$defs/present"},
+			"c": {"$ref": "other.json"}
+		},
+		"then": {"$dynamicRef": "#nowhere"},
+		"$defs": {"present": {"type": "string"}}
+	}`)
+
+	t.Run("lenient", func(t *testing.T) {
+		schema, err := NewCompiler().Compile(schemaJSON)
+		if err != nil {
+			t.Fatalf("Expected lenient compilation to succeed, got %v", err)
+		}
+
+		warnings := schema.CompileWarnings()
+		if len(warnings) != 3 {
+			t.Fatalf("Expected 3 warnings, got %d: %v", len(warnings), warnings)
+		}
+
+		expected := []struct{ keyword, location, uri string }{
+			{"$ref", "/properties/a/$ref", "http://example.com/root#/$defs/missing"},
+			{"$ref", "/properties/c/$ref", "http://example.com/other.json"},
+			{"$dynamicRef", "/then/$dynamicRef", "http://example.com/root#nowhere"},
+		}
+		for i, want := range expected {
+			got := warnings[i]
+			if got.Keyword != want.keyword || got.Location != want.location || got.URI != want.uri {
+				t.Errorf("Warning %d: expected %+v, got %s %s %s", i, want, got.Keyword, got.Location, got.URI)
+			}
+		}
+
+		if (*schema.Properties)["b"].ResolvedRef == nil {
+			t.Error("Expected resolvable references to be resolved despite earlier failures")
+		}
+	})
+
+	t.Run("strict", func(t *testing.T) {
+		_, err := NewCompiler().SetStrictRefs(true).Compile(schemaJSON)
+
+		var compileErr *CompileError
+		if !errors.As(err, &compileErr) {
+			t.Fatalf("Expected a *CompileError, got %v", err)
+		}
+		if len(compileErr.References) != 3 {
+			t.Errorf("Expected 3 unresolved references, got %d", len(compileErr.References))
+		}
+		if compileErr.URI != "http://example.com/root" {
+			t.Errorf("Expected the schema URI in the error, got %q", compileErr.URI)
+		}
+		if !errors.Is(err, ErrFailedToResolveReference) || !errors.Is(err, ErrFailedToResolveAnchor) {
+			t.Errorf("Expected the error to match the resolution causes, got %v", err)
+		}
+	})
+
+	t.Run("tf references are resolved when evaluating", func(t *testing.T) {
+		schema, err := NewCompiler().SetStrictRefs(true).Compile([]byte(`{"$ref": "tf://types/unknown"}`))
+		if err != nil {
+			t.Fatalf("Expected tf:// references to be ignored at compile time, got %v", err)
+		}
+		if len(schema.CompileWarnings()) != 0 {
+			t.Errorf("Expected no warnings, got %v", schema.CompileWarnings())
+		}
+	})
+}
+
+func TestCompileValidateSchemas(t *testing.T) {
+	compiler := NewCompiler().SetValidateSchemas(true)
+
+	_, err := compiler.Compile([]byte(`{"type": "strnig", "properties": {"name": {"minLength": "five"}}}`), "http://example.com/invalid")
+
+	var invalid *InvalidSchemaError
+	if !errors.As(err, &invalid) {
+		t.Fatalf("Expected an *InvalidSchemaError, got %v", err)
+	}
+	if !errors.Is(err, ErrInvalidSchema) {
+		t.Errorf("Expected the error to match ErrInvalidSchema")
+	}
+	if invalid.URI != "http://example.com/invalid" || invalid.Result.IsValid() {
+		t.Errorf("Expected the URI and a failed result, got %q and %v", invalid.URI, invalid.Result.IsValid())
+	}
+	for _, location := range []string{"/type", "/properties/name/minLength"} {
+		if !strings.Contains(err.Error(), location+": ") {
+			t.Errorf("Expected the error to locate %s, got %v", location, err)
+		}
+	}
+
+	i18n, err := GetI18n()
+	if err != nil {
+		t.Fatalf("Failed to load i18n: %v", err)
+	}
+	if list := invalid.Result.ToLocalizeList(i18n.NewLocalizer("zh-Hans"), false); list.Valid {
+		t.Error("Expected the localized result to be invalid")
+	}
+
+	if _, err := compiler.Compile([]byte(`{"type": "string", "properties": {"name": {"minLength": 5}}}`)); err != nil {
+		t.Errorf("Expected a valid schema to compile, got %v", err)
+	}
+}
+
+func TestValidateSchema(t *testing.T) {
+	compiler := NewCompiler()
+
+	result, err := compiler.ValidateSchema([]byte(`{"$defs": {"a": {"required": "name"}}, "items": [true]}`))
+	if err != nil {
+		t.Fatalf("Failed to validate schema: %v", err)
+	}
+	if result.IsValid() {
+		t.Error("Expected the schema to be invalid")
+	}
+
+	result, err = compiler.ValidateSchema([]byte(`{"$defs": {"a": {"required": ["name"]}}, "items": true}`))
+	if err != nil || !result.IsValid() {
+		t.Errorf("Expected the schema to be valid, got %v", err)
+	}
+
+	if _, err := compiler.ValidateSchema([]byte(`{"type":`)); !errors.Is(err, ErrJSONUnmarshalError) {
+		t.Errorf("Expected ErrJSONUnmarshalError, got %v", err)
+	}
+}
+
+func TestEmbeddedMetaSchemas(t *testing.T) {
+	compiler := NewCompiler()
+	compiler.RegisterLoaderContext("https", func(ctx context.Context, url string) (io.ReadCloser, error) {
+		t.Errorf("Expected %s to be served from the embedded meta-schemas", url)
+		return nil, ErrFailedToFetch
+	})
+
+	for _, uri := range []string{
+		MetaSchemaURL,
+		"https://json-schema.org/draft/2020-12/meta/core",
+		"https://json-schema.org/draft/2020-12/meta/format-assertion",
+	} {
+		schema, err := compiler.GetSchema(uri)
+		if err != nil {
+			t.Fatalf("Failed to load %s: %v", uri, err)
+		}
+		if schema.ID != uri {
+			t.Errorf("Expected schema with ID %s, got %s", uri, schema.ID)
+		}
+	}
+
+	schema, err := compiler.SetStrictRefs(true).Compile([]byte(`{
+		"$schema": "https://json-schema.org/draft/2020-12/schema",
+		"$ref": "https://json-schema.org/draft/2020-12/schema"
+	}`))
+	if err != nil {
+		t.Fatalf("Failed to compile schema referencing the meta-schema: %v", err)
+	}
+	if schema.Validate(map[string]interface{}{"minLength": -1}).IsValid() {
+		t.Error("Expected an invalid schema to fail validation against the meta-schema")
+	}
+}
@@ -18,15 +18,11 @@ func evaluateConditional(schema *Schema, instance interface{}, evaluatedProps ma
 	}
 
 	// Evaluate the 'if' condition
-	ifResult, ifEvaluatedProps, ifEvaluatedItems := schema.If.evaluate(instance, dynamicScope)
+	ifResult, ifEvaluatedProps, ifEvaluatedItems := schema.If.evaluateIn(instance, "/if", dynamicScope)
 
 	results := []*EvaluationResult{}
 
 	if ifResult != nil {
-		ifResult.SetEvaluationPath("/if").
-			SetSchemaLocation(schema.GetSchemaLocation("/if")).
-			SetInstanceLocation("")
-
 		results = append(results, ifResult)
 
 		if ifResult.IsValid() {
@@ -35,13 +31,9 @@ func evaluateConditional(schema *Schema, instance interface{}, evaluatedProps ma
 			mergeIntMaps(evaluatedItems, ifEvaluatedItems)
 
 			if schema.Then != nil {
-				thenResult, thenEvaluatedProps, thenEvaluatedItems := schema.Then.evaluate(instance, dynamicScope)
+				thenResult, thenEvaluatedProps, thenEvaluatedItems := schema.Then.evaluateIn(instance, "/then", dynamicScope)
 
 				if thenResult != nil {
-					thenResult.SetEvaluationPath("/then").
-						SetSchemaLocation(schema.GetSchemaLocation("/then")).
-						SetInstanceLocation("")
-
 					results = append(results, thenResult)
 
 					if !thenResult.IsValid() {
@@ -55,7 +47,7 @@ func evaluateConditional(schema *Schema, instance interface{}, evaluatedProps ma
 				}
 			}
 		} else if schema.Else != nil {
-			elseResult, elseEvaluatedProps, elseEvaluatedItems := schema.Else.evaluate(instance, dynamicScope)
+			elseResult, elseEvaluatedProps, elseEvaluatedItems := schema.Else.evaluateIn(instance, "/else", dynamicScope)
 			if elseResult != nil {
 				results = append(results, elseResult)
 
@@ -1,6 +1,6 @@
 package jsonschema
 
-import "fmt"
+import "strconv"
 
 // EvaluateContains checks if at least one element in an array meets the conditions specified by the 'contains' keyword.
 // It follows the JSON Schema Draft 2020-12:
@@ -15,7 +15,7 @@ import "fmt"
 // This function provides detailed feedback on element validation and gathers comprehensive annotations.
 //
 // Reference: https://json-schema.org/draft/2020-12/json-schema-core#name-contains
-func evaluateContains(schema *Schema, data []interface{}, evaluatedProps map[string]bool, evaluatedItems map[int]bool, dynamicScope *DynamicScope) ([]*EvaluationResult, *EvaluationError) {
+func evaluateContains(schema *Schema, data []interface{}, result *EvaluationResult, evaluatedProps map[string]bool, evaluatedItems map[int]bool, dynamicScope *DynamicScope) ([]*EvaluationResult, *EvaluationError) {
 	if schema.Contains == nil {
 		// No 'contains' constraint is defined, skip further checks.
 		return nil, nil
@@ -24,19 +24,36 @@ func evaluateContains(schema *Schema, data []interface{}, evaluatedProps map[str
 	results := []*EvaluationResult{}
 
 	var validCount int
+	matched := []int{}
 	for i, item := range data {
-		result, _, _ := schema.Contains.evaluate(item, dynamicScope)
+		itemResult, _, _ := schema.Contains.evaluateAt(item, "/contains", strconv.Itoa(i), dynamicScope)
 
-		if result != nil {
-			result.SetEvaluationPath("/contains").
-				SetSchemaLocation(schema.GetSchemaLocation("/contains")).
-				SetInstanceLocation(fmt.Sprintf("/%d", i))
-
-			if result.IsValid() {
+		if itemResult != nil {
+			if itemResult.IsValid() {
+				// Only the matching items are kept, as the others do not make the keyword fail.
+				results = append(results, itemResult)
 				validCount++
-				evaluatedItems[i] = true // Mark this item as evaluated
+				if dynamicScope.annotates() {
+					matched = append(matched, i)
+				}
+				if evaluatedItems != nil {
+					evaluatedItems[i] = true // Mark this item as evaluated
+				}
 			}
 		}
+
+		if dynamicScope.isCanceled() {
+			return results, newEvaluationCanceledError(dynamicScope.Context().Err())
+		}
+	}
+
+	// The annotation is the indexes the subschema validated, or true if it validated every item.
+	if dynamicScope.annotates() {
+		if len(matched) == len(data) {
+			result.AddAnnotation("contains", true)
+		} else {
+			result.AddAnnotation("contains", matched)
+		}
 	}
 
 	// Handle 'minContains' logic
@@ -0,0 +1,264 @@
+package jsonschema
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+)
+
+// maxJSONDepth bounds the nesting of arrays and objects in a decoded instance, as encoding/json does.
+const maxJSONDepth = 10000
+
+// JSONParseError describes a JSON document passed to ValidateJSON or ValidateReader that could not be decoded.
+// It unwraps to ErrInvalidJSON and to the underlying error, which is ErrDuplicateKey for repeated object keys.
+type JSONParseError struct {
+	Offset int64 // Number of bytes read when the error was detected.
+	Line   int   // Line of the last byte read, starting at 1.
+	Column int   // Column in bytes of the last byte read, starting at 1.
+	Err    error // Underlying error.
+}
+
+// Error implements the error interface.
+func (e *JSONParseError) Error() string {
+	return fmt.Sprintf("invalid JSON at line %d, column %d: %v", e.Line, e.Column, e.Err)
+}
+
+// Unwrap returns ErrInvalidJSON and the underlying error.
+func (e *JSONParseError) Unwrap() []error {
+	return []error{ErrInvalidJSON, e.Err}
+}
+
+// ValidateJSON decodes a JSON document and checks if it conforms to the schema.
+// Numbers are decoded as json.Number, so they keep their exact value whatever their size or precision.
+// A malformed document, or one with an object repeating a key, returns a *JSONParseError.
+func (s *Schema) ValidateJSON(data []byte) (*EvaluationResult, error) {
+	return s.ValidateReader(bytes.NewReader(data))
+}
+
+// ValidateReader decodes a single JSON document from r and checks if it conforms to the schema.
+// Anything but whitespace following the document is an error, and an error reading r is returned as is.
+// See ValidateJSON for details.
+func (s *Schema) ValidateReader(r io.Reader) (*EvaluationResult, error) {
+	instance, err := decodeJSON(r)
+	if err != nil {
+		return nil, err
+	}
+	return s.Validate(instance), nil
+}
+
+// decodeJSON decodes a single JSON document into generic values, using json.Number for numbers.
+func decodeJSON(r io.Reader) (interface{}, error) {
+	d := newJSONDecoder(r)
+	value, err := d.value(0)
+	if err != nil {
+		return nil, err
+	}
+	if err := d.end(); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+// jsonDecoder builds generic values from the tokens of a JSON document, which encoding/json checks for syntax.
+// Unlike the rest of the package, it relies on encoding/json rather than github.com/goccy/go-json, whose
+// Token method accepts malformed documents, such as an object member without a colon, locates syntax errors
+// at the end of the data read rather than where they are, and hides the errors of the reader. The json.Number and json.Delim of goccy/go-json
+// are aliases of the ones of encoding/json, so the values decoded are the same.
+type jsonDecoder struct {
+	decoder *json.Decoder
+	lines   *lineReader
+}
+
+// newJSONDecoder returns a jsonDecoder reading from r, which decodes numbers as json.Number.
+func newJSONDecoder(r io.Reader) *jsonDecoder {
+	lines := &lineReader{r: r}
+	decoder := json.NewDecoder(lines)
+	decoder.UseNumber()
+	return &jsonDecoder{decoder: decoder, lines: lines}
+}
+
+// value decodes the next value, at the given nesting depth.
+func (d *jsonDecoder) value(depth int) (interface{}, error) {
+	token, err := d.token()
+	if err != nil {
+		return nil, err
+	}
+	if delim, ok := token.(json.Delim); ok {
+		return d.container(delim, depth+1)
+	}
+	return token, nil
+}
+
+// container decodes the array or object opened by delim, which is at the given nesting depth.
+func (d *jsonDecoder) container(delim json.Delim, depth int) (interface{}, error) {
+	if err := d.nest(depth); err != nil {
+		return nil, err
+	}
+	if delim == '[' {
+		return d.array(depth)
+	}
+	return d.object(depth)
+}
+
+// array decodes the elements of an array and its closing bracket.
+func (d *jsonDecoder) array(depth int) (interface{}, error) {
+	array := []interface{}{}
+	for d.decoder.More() {
+		item, err := d.value(depth)
+		if err != nil {
+			return nil, err
+		}
+		array = append(array, item)
+	}
+	if _, err := d.token(); err != nil {
+		return nil, err
+	}
+	return array, nil
+}
+
+// object decodes the members of an object and its closing brace.
+func (d *jsonDecoder) object(depth int) (interface{}, error) {
+	object := map[string]interface{}{}
+	for d.decoder.More() {
+		key, err := d.key(object)
+		if err != nil {
+			return nil, err
+		}
+		if object[key], err = d.value(depth); err != nil {
+			return nil, err
+		}
+	}
+	if _, err := d.token(); err != nil {
+		return nil, err
+	}
+	return object, nil
+}
+
+// key reads the key of the next member of an object, rejecting the keys already in object.
+func (d *jsonDecoder) key(object map[string]interface{}) (string, error) {
+	token, err := d.token()
+	if err != nil {
+		return "", err
+	}
+	key, _ := token.(string)
+	if _, exists := object[key]; exists {
+		return "", d.error(fmt.Errorf("%w %q", ErrDuplicateKey, key))
+	}
+	return key, nil
+}
+
+// nest checks that an array or object at the given nesting depth is not nested too deeply.
+func (d *jsonDecoder) nest(depth int) error {
+	if depth > maxJSONDepth {
+		return d.errorf("exceeded max depth of %d", maxJSONDepth)
+	}
+	return nil
+}
+
+// end checks that nothing but whitespace follows the value decoded last.
+func (d *jsonDecoder) end() error {
+	if _, err := d.decoder.Token(); err == nil {
+		return d.errorf("unexpected data after top-level value")
+	} else if !errors.Is(err, io.EOF) {
+		return d.error(err)
+	}
+	return nil
+}
+
+// token reads the next token, an early end of the document being an error.
+func (d *jsonDecoder) token() (json.Token, error) {
+	token, err := d.decoder.Token()
+	if errors.Is(err, io.EOF) {
+		err = io.ErrUnexpectedEOF
+	}
+	if err != nil {
+		return nil, d.error(err)
+	}
+	d.lines.consume(d.decoder.InputOffset())
+	return token, nil
+}
+
+// error locates err at the current position in the document.
+func (d *jsonDecoder) error(err error) error {
+	if d.lines.err != nil && errors.Is(err, d.lines.err) {
+		return err
+	}
+
+	offset := d.decoder.InputOffset()
+	var syntaxErr *json.SyntaxError
+	if errors.As(err, &syntaxErr) {
+		offset = syntaxErr.Offset
+	} else if errors.Is(err, io.ErrUnexpectedEOF) {
+		offset = d.lines.read
+	}
+	line, column := d.lines.position(offset)
+	return &JSONParseError{Offset: offset, Line: line, Column: column, Err: err}
+}
+
+// errorf locates a formatted error at the current position in the document.
+func (d *jsonDecoder) errorf(format string, args ...interface{}) error {
+	return d.error(fmt.Errorf(format, args...))
+}
+
+// lineReader counts the lines of the bytes read through it, and records the error reading failed with.
+// The lines the decoder is done with are only counted: the starts of lines are only kept for the bytes
+// read ahead of the decoder, so that memory does not grow with the length of the document.
+type lineReader struct {
+	r         io.Reader
+	read      int64
+	line      int     // Number of lines before the bytes the decoder is not done with.
+	lineStart int64   // Offset of the start of the last of these lines.
+	ahead     []int64 // Offsets of the bytes following each newline the decoder is not done with.
+	err       error
+}
+
+// Read implements the io.Reader interface.
+func (l *lineReader) Read(p []byte) (int, error) {
+	n, err := l.r.Read(p)
+	for i, b := range p[:n] {
+		if b == '\n' {
+			l.ahead = append(l.ahead, l.read+int64(i)+1)
+		}
+	}
+	l.read += int64(n)
+	if err != nil && !errors.Is(err, io.EOF) {
+		l.err = err
+	}
+	return n, err
+}
+
+// consume records that the decoder is done with the bytes before offset, which no error is located in.
+func (l *lineReader) consume(offset int64) {
+	i := 0
+	for i < len(l.ahead) && l.ahead[i] < offset {
+		i++
+	}
+	if i == 0 {
+		return
+	}
+
+	l.line += i
+	l.lineStart = l.ahead[i-1]
+	if i == len(l.ahead) {
+		l.ahead = l.ahead[:0]
+	} else {
+		l.ahead = l.ahead[i:]
+	}
+}
+
+// position returns the line and column of the last byte before offset, both starting at 1. The offset
+// must not be before the bytes the decoder is done with.
+func (l *lineReader) position(offset int64) (line, column int) {
+	if offset > 0 {
+		offset--
+	}
+	i := sort.Search(len(l.ahead), func(i int) bool { return l.ahead[i] > offset })
+	start := l.lineStart
+	if i > 0 {
+		start = l.ahead[i-1]
+	}
+	return l.line + i + 1, int(offset-start) + 1
+}
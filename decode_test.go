@@ -0,0 +1,108 @@
+package jsonschema
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/test-go/testify/assert"
+)
+
+func TestValidateJSON(t *testing.T) {
+	t.Run("precise numbers", func(t *testing.T) {
+		schema, err := NewCompiler().Compile([]byte(`{
+			"properties": {
+				"id": {"type": "integer", "maximum": 18446744073709551615},
+				"price": {"multipleOf": 0.01},
+				"rate": {"const": 0.1}
+			}
+		}`))
+		assert.NoError(t, err)
+
+		result, err := schema.ValidateJSON([]byte(`{"id": 18446744073709551615, "price": 19.99, "rate": 0.1}`))
+		assert.NoError(t, err)
+		assert.True(t, result.IsValid())
+
+		result, err = schema.ValidateJSON([]byte(`{"id": 18446744073709551616}`))
+		assert.NoError(t, err)
+		assert.False(t, result.IsValid(), "Expected an integer beyond float64 precision to be compared exactly")
+
+		result, err = schema.ValidateJSON([]byte(`{"id": 1.0e2, "price": 19.991}`))
+		assert.NoError(t, err)
+		assert.False(t, result.IsValid())
+	})
+
+	t.Run("equal numbers", func(t *testing.T) {
+		schema, err := NewCompiler().Compile([]byte(`{"items": {"enum": [1, 2.5]}, "uniqueItems": true}`))
+		assert.NoError(t, err)
+
+		result, err := schema.ValidateJSON([]byte(`[1.0, 2.50]`))
+		assert.NoError(t, err)
+		assert.True(t, result.IsValid())
+
+		result, err = schema.ValidateJSON([]byte(`[1, 1.0]`))
+		assert.NoError(t, err)
+		assert.False(t, result.IsValid())
+	})
+
+	t.Run("parse errors", func(t *testing.T) {
+		schema, err := NewCompiler().Compile([]byte(`{}`))
+		assert.NoError(t, err)
+
+		tests := []struct {
+			data   string
+			line   int
+			column int
+			err    error
+		}{
+			{"{\n  \"a\": [1, }\n}", 2, 10, nil},
+			{"{\"a\" 1}", 1, 6, nil},
+			{"{\n  \"a\": 1,\n  \"a\": 2\n}", 3, 5, ErrDuplicateKey},
+			{"[1, 2", 1, 5, nil},
+			{"", 1, 1, nil},
+			{"{} []", 1, 4, nil},
+		}
+		for _, tt := range tests {
+			result, err := schema.ValidateJSON([]byte(tt.data))
+			assert.Nil(t, result)
+
+			var parseErr *JSONParseError
+			if assert.True(t, errors.As(err, &parseErr), "Expected a JSONParseError for %q, got %v", tt.data, err) {
+				assert.True(t, errors.Is(err, ErrInvalidJSON))
+				assert.Equal(t, tt.line, parseErr.Line, "Unexpected line for %q", tt.data)
+				assert.Equal(t, tt.column, parseErr.Column, "Unexpected column for %q", tt.data)
+			}
+			if tt.err != nil {
+				assert.True(t, errors.Is(err, tt.err), "Expected %v, got %v", tt.err, err)
+			}
+		}
+	})
+
+	t.Run("long documents", func(t *testing.T) {
+		const lines = 100000
+		data := "[\n" + strings.Repeat("  1,\n", lines) + "  }\n]"
+
+		d := newJSONDecoder(strings.NewReader(data))
+		_, err := d.value(0)
+		var parseErr *JSONParseError
+		if assert.True(t, errors.As(err, &parseErr), "Expected a JSONParseError, got %v", err) {
+			assert.Equal(t, lines+1, parseErr.Line)
+			assert.Equal(t, 4, parseErr.Column)
+		}
+		assert.True(t, cap(d.lines.ahead) < 1000, "Expected only the lines read ahead to be kept, got %d", cap(d.lines.ahead))
+	})
+
+	t.Run("reader", func(t *testing.T) {
+		schema, err := NewCompiler().Compile([]byte(`{"type": "array", "items": {"type": "integer"}}`))
+		assert.NoError(t, err)
+
+		result, err := schema.ValidateReader(iotest.OneByteReader(strings.NewReader("[1, 2, 3]\n")))
+		assert.NoError(t, err)
+		assert.True(t, result.IsValid())
+
+		_, err = schema.ValidateReader(iotest.ErrReader(iotest.ErrTimeout))
+		assert.True(t, errors.Is(err, iotest.ErrTimeout))
+		assert.False(t, errors.Is(err, ErrInvalidJSON))
+	})
+}
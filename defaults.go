@@ -0,0 +1,106 @@
+package jsonschema
+
+import "strconv"
+
+// ValidateAndApplyDefaults checks if the instance, completed with the default values declared by the
+// schema, conforms to the schema. Along with the result, it returns a copy of the instance in which
+// every missing property whose schema has a "default" is set to a copy of that value. Defaults are
+// applied through "properties", "items", "prefixItems", "$ref", "allOf" and the "then" or "else" branch
+// taken by "if", including within the default values themselves, although a default is not applied
+// again within itself, as a recursive schema would then never end. The instance is left untouched.
+func (s *Schema) ValidateAndApplyDefaults(instance interface{}) (*EvaluationResult, interface{}) {
+	normalized, err := normalizeInstance(instance)
+	if err != nil {
+		return s.Validate(instance), instance
+	}
+
+	completed := copyJSON(normalized)
+	completed = s.applyDefaults(completed, make(map[*Schema]bool), NewDynamicScope())
+
+	return s.Validate(completed), completed
+}
+
+// applyDefaults sets the missing properties of instance, which it may modify, to their default value
+// and returns the completed instance. The schemas in filling are those whose default is being completed
+// by the caller, which are not applied again.
+func (s *Schema) applyDefaults(instance interface{}, filling map[*Schema]bool, dynamicScope *DynamicScope) interface{} {
+	if s.Boolean != nil {
+		return instance
+	}
+	dynamicScope.Push(s)
+	defer dynamicScope.Pop()
+
+	if s.ResolvedRef != nil {
+		instance = s.ResolvedRef.applyDefaults(instance, filling, dynamicScope)
+	}
+	if !s.hasVocabulary(VocabularyApplicator) {
+		return instance
+	}
+
+	switch value := instance.(type) {
+	case map[string]interface{}:
+		if s.Properties != nil {
+			for name, property := range *s.Properties {
+				if _, exists := value[name]; exists {
+					value[name] = property.applyDefaultsAt(value[name], name, filling, dynamicScope)
+				} else if defaultIsSpecified(property) && !filling[property] {
+					// The default is completed in turn, unless it is already being completed.
+					filling[property] = true
+					value[name] = property.applyDefaultsAt(copyJSON(property.Default), name, filling, dynamicScope)
+					delete(filling, property)
+				}
+			}
+		}
+	case []interface{}:
+		for i := range value {
+			if i < len(s.PrefixItems) {
+				value[i] = s.PrefixItems[i].applyDefaultsAt(value[i], strconv.Itoa(i), filling, dynamicScope)
+			} else if s.Items != nil {
+				value[i] = s.Items.applyDefaultsAt(value[i], strconv.Itoa(i), filling, dynamicScope)
+			}
+		}
+	}
+
+	for _, member := range s.AllOf {
+		instance = member.applyDefaults(instance, filling, dynamicScope)
+	}
+
+	if s.If != nil {
+		result, _, _ := s.If.evaluateIn(instance, "/if", dynamicScope)
+		if result.IsValid() && s.Then != nil {
+			instance = s.Then.applyDefaults(instance, filling, dynamicScope)
+		} else if !result.IsValid() && s.Else != nil {
+			instance = s.Else.applyDefaults(instance, filling, dynamicScope)
+		}
+	}
+
+	return instance
+}
+
+// applyDefaultsAt applies defaults to a child of the current instance, the member or item named by
+// segment, keeping track of its location in the dynamic scope.
+func (s *Schema) applyDefaultsAt(instance interface{}, segment string, filling map[*Schema]bool, dynamicScope *DynamicScope) interface{} {
+	dynamicScope.enterInstance(segment)
+	defer dynamicScope.leaveInstance()
+
+	return s.applyDefaults(instance, filling, dynamicScope)
+}
+
+// copyJSON returns a deep copy of a generic JSON value, whose maps and slices are not shared with v.
+func copyJSON(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		copied := make(map[string]interface{}, len(v))
+		for key, value := range v {
+			copied[key] = copyJSON(value)
+		}
+		return copied
+	case []interface{}:
+		copied := make([]interface{}, len(v))
+		for i, value := range v {
+			copied[i] = copyJSON(value)
+		}
+		return copied
+	}
+	return v
+}
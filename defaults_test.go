@@ -0,0 +1,119 @@
+package jsonschema
+
+import (
+	"testing"
+
+	"github.com/test-go/testify/assert"
+)
+
+func TestValidateAndApplyDefaults(t *testing.T) {
+	schema, err := NewCompiler().Compile([]byte(`{
+		"$defs": {
+			"address": {
+				"type": "object",
+				"properties": {
+					"country": {"type": "string", "default": "FR"}
+				}
+			}
+		},
+		"type": "object",
+		"required": ["role"],
+		"properties": {
+			"role": {"enum": ["admin", "user"], "default": "user"},
+			"settings": {
+				"type": "object",
+				"default": {},
+				"properties": {
+					"theme": {"default": "light"},
+					"tags": {"default": ["a"]}
+				}
+			},
+			"address": {"$ref": "#/$defs/address"},
+			"points": {
+				"prefixItems": [{"properties": {"x": {"default": 0}}}],
+				"items": {"properties": {"y": {"default": 1}}}
+			}
+		},
+		"allOf": [
+			{"properties": {"active": {"default": true}}}
+		],
+		"if": {"properties": {"role": {"const": "admin"}}},
+		"then": {"properties": {"level": {"default": 10}}},
+		"else": {"properties": {"level": {"default": 1}}}
+	}`))
+	assert.NoError(t, err)
+
+	t.Run("missing properties", func(t *testing.T) {
+		instance := map[string]interface{}{
+			"address": map[string]interface{}{},
+			"points":  []interface{}{map[string]interface{}{}, map[string]interface{}{}},
+		}
+
+		result, completed := schema.ValidateAndApplyDefaults(instance)
+		assert.True(t, result.IsValid())
+		assert.Equal(t, map[string]interface{}{
+			"role":     "user",
+			"settings": map[string]interface{}{"theme": "light", "tags": []interface{}{"a"}},
+			"address":  map[string]interface{}{"country": "FR"},
+			"points": []interface{}{
+				map[string]interface{}{"x": float64(0)},
+				map[string]interface{}{"y": float64(1)},
+			},
+			"active": true,
+			"level":  float64(1),
+		}, completed)
+
+		assert.Equal(t, map[string]interface{}{
+			"address": map[string]interface{}{},
+			"points":  []interface{}{map[string]interface{}{}, map[string]interface{}{}},
+		}, instance, "Expected the instance to be left untouched")
+	})
+
+	t.Run("branch taken", func(t *testing.T) {
+		result, completed := schema.ValidateAndApplyDefaults(map[string]interface{}{"role": "admin", "active": false})
+		assert.True(t, result.IsValid())
+		assert.Equal(t, float64(10), completed.(map[string]interface{})["level"])
+		assert.Equal(t, false, completed.(map[string]interface{})["active"])
+	})
+
+	t.Run("defaults are copied", func(t *testing.T) {
+		_, first := schema.ValidateAndApplyDefaults(map[string]interface{}{})
+		first.(map[string]interface{})["settings"].(map[string]interface{})["tags"].([]interface{})[0] = "changed"
+
+		_, second := schema.ValidateAndApplyDefaults(map[string]interface{}{})
+		assert.Equal(t, []interface{}{"a"}, second.(map[string]interface{})["settings"].(map[string]interface{})["tags"])
+		assert.Equal(t, map[string]interface{}{}, (*schema.Properties)["settings"].Default)
+	})
+
+	t.Run("invalid instance", func(t *testing.T) {
+		result, completed := schema.ValidateAndApplyDefaults(map[string]interface{}{"role": "guest"})
+		assert.False(t, result.IsValid())
+		assert.Equal(t, "guest", completed.(map[string]interface{})["role"])
+	})
+
+	t.Run("recursive default", func(t *testing.T) {
+		schema, err := NewCompiler().Compile([]byte(`{
+			"properties": {
+				"name": {"default": "node"},
+				"child": {"$ref": "#", "default": {}}
+			}
+		}`))
+		assert.NoError(t, err)
+
+		result, completed := schema.ValidateAndApplyDefaults(map[string]interface{}{})
+		assert.True(t, result.IsValid())
+		assert.Equal(t, map[string]interface{}{
+			"name":  "node",
+			"child": map[string]interface{}{"name": "node"},
+		}, completed)
+
+		_, completed = schema.ValidateAndApplyDefaults(map[string]interface{}{"child": map[string]interface{}{"child": map[string]interface{}{}}})
+		assert.Equal(t, map[string]interface{}{
+			"name": "node",
+			"child": map[string]interface{}{
+				"name":  "node",
+				"child": map[string]interface{}{"name": "node", "child": map[string]interface{}{"name": "node"}},
+			},
+		}, completed)
+	})
+}
@@ -27,15 +27,12 @@ func evaluateDependentSchemas(schema *Schema, instance interface{}, evaluatedPro
 	results := []*EvaluationResult{}
 
 	for propName, depSchema := range schema.DependentSchemas {
+		if dynamicScope.stops(len(invalid_properties) > 0) {
+			break
+		}
 		if _, exists := object[propName]; exists {
 			if depSchema != nil {
-				result, schemaEvaluatedProps, schemaEvaluatedItems := depSchema.evaluate(object, dynamicScope)
-				if result != nil {
-					result.SetEvaluationPath(fmt.Sprintf("/dependentSchemas/%s", propName)).
-						SetSchemaLocation(schema.GetSchemaLocation(fmt.Sprintf("/dependentSchemas/%s", propName))).
-						SetInstanceLocation(fmt.Sprintf("/%s", propName))
-				}
-
+				result, schemaEvaluatedProps, schemaEvaluatedItems := depSchema.evaluateIn(object, "/dependentSchemas/"+escapeJSONPointerSegment(propName), dynamicScope)
 				if result.IsValid() {
 					// Merge maps only if dependent schema validation is successful
 					mergeStringMaps(evaluatedProps, schemaEvaluatedProps)
@@ -0,0 +1,162 @@
+package jsonschema
+
+import (
+	"context"
+	"strconv"
+)
+
+// Direction is the way data validated against a schema travels through an API, which decides whether
+// the values the schema marks readOnly or writeOnly may be present, see WithDirection.
+type Direction int
+
+const (
+	// AnyDirection validates data regardless of readOnly and writeOnly, which only annotate it.
+	AnyDirection Direction = iota
+	// Request validates data sent to an API, which must not hold the values marked readOnly.
+	Request
+	// Response validates data returned by an API, which must not hold the values marked writeOnly.
+	Response
+)
+
+// directionKey is the context key of the direction of the validation.
+type directionKey struct{}
+
+// WithDirection returns a copy of ctx which makes ValidateContext validate data travelling in direction.
+// A Request is invalid if it holds a value whose schema sets readOnly, and a Response if it holds a value
+// whose schema sets writeOnly. A property required by an object but marked readOnly, respectively
+// writeOnly, in its "properties" may then be missing, as OpenAPI specifies.
+func WithDirection(ctx context.Context, direction Direction) context.Context {
+	return context.WithValue(ctx, directionKey{}, direction)
+}
+
+// ValidateRequest checks if the given instance conforms to the schema as data sent to an API, see
+// WithDirection.
+func (s *Schema) ValidateRequest(instance interface{}) *EvaluationResult {
+	return s.ValidateContext(WithDirection(context.Background(), Request), instance)
+}
+
+// ValidateResponse checks if the given instance conforms to the schema as data returned by an API, see
+// WithDirection.
+func (s *Schema) ValidateResponse(instance interface{}) *EvaluationResult {
+	return s.ValidateContext(WithDirection(context.Background(), Response), instance)
+}
+
+// marks reports whether schema sets the keyword forbidding its values in data travelling in direction.
+func (d Direction) marks(schema *Schema) bool {
+	if schema == nil {
+		return false
+	}
+
+	switch d {
+	case Request:
+		return schema.ReadOnly != nil && *schema.ReadOnly
+	case Response:
+		return schema.WriteOnly != nil && *schema.WriteOnly
+	default:
+		return false
+	}
+}
+
+// forbids reports whether data travelling in direction must not hold a value conforming to schema, which
+// is the case if schema, or a schema it references through $ref, marks it.
+func (d Direction) forbids(schema *Schema) bool {
+	if d == AnyDirection {
+		return false
+	}
+
+	for visited := map[*Schema]bool{}; schema != nil && !visited[schema]; schema = schema.ResolvedRef {
+		if d.marks(schema) {
+			return true
+		}
+		visited[schema] = true
+	}
+	return false
+}
+
+// exempts reports whether the property propName may be missing from an object conforming to schema,
+// although required, as data travelling in direction must not hold it.
+func (d Direction) exempts(schema *Schema, propName string) bool {
+	if schema.Properties == nil {
+		return false
+	}
+
+	return d.forbids((*schema.Properties)[propName])
+}
+
+// evaluateDirection checks that the instance may travel in the direction of the validation, given the
+// readOnly and writeOnly keywords of the schema.
+func evaluateDirection(schema *Schema, direction Direction) *EvaluationError {
+	if !direction.marks(schema) {
+		return nil
+	}
+
+	if direction == Request {
+		return NewEvaluationError("readOnly", "read_only_value", "Value is read-only and cannot be sent in a request")
+	}
+	return NewEvaluationError("writeOnly", "write_only_value", "Value is write-only and cannot be returned in a response")
+}
+
+// Strip returns a copy of instance without the properties that data travelling in direction must not
+// hold: the properties marked readOnly for a Request, such as an identifier assigned by the server, and
+// the ones marked writeOnly for a Response, such as a password. A property is stripped if any schema
+// applied to it marks it, even one that failed, so that an invalid instance does not leak a value. Like
+// ValidateContext, Strip accepts any Go value encodable as JSON, and returns the generic representation
+// of JSON values. The objects and arrays are always copied, so that instance is left unchanged.
+func (s *Schema) Strip(direction Direction, instance interface{}) (interface{}, error) {
+	return s.StripContext(context.Background(), direction, instance)
+}
+
+// StripContext is like Strip, but stops once ctx is canceled or its deadline expires, returning the
+// error of ctx. Loaders used during the evaluation receive ctx. Every schema is applied whatever the
+// fail-fast mode of ctx, as the properties to strip are only known once all of them are evaluated.
+func (s *Schema) StripContext(ctx context.Context, direction Direction, instance interface{}) (interface{}, error) {
+	normalized, err := normalizeInstance(instance)
+	if err != nil {
+		return nil, err
+	}
+
+	dynamicScope := NewDynamicScope()
+	dynamicScope.ctx = ctx
+	result, _, _ := s.evaluate(normalized, dynamicScope)
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	stripped := make(map[string]bool)
+	var walk func(result *EvaluationResult)
+	walk = func(result *EvaluationResult) {
+		if direction.marks(result.schema) {
+			stripped[result.InstanceLocation] = true
+		}
+		for _, detail := range result.Details {
+			walk(detail)
+		}
+	}
+	walk(result.locate())
+
+	return stripValue(normalized, "", stripped), nil
+}
+
+// stripValue returns a copy of value, found at the JSON Pointer location, without the properties whose
+// location is in stripped.
+func stripValue(value interface{}, location string, stripped map[string]bool) interface{} {
+	switch value := value.(type) {
+	case map[string]interface{}:
+		object := make(map[string]interface{}, len(value))
+		for key, child := range value {
+			childLocation := location + "/" + escapeJSONPointerSegment(key)
+			if !stripped[childLocation] {
+				object[key] = stripValue(child, childLocation, stripped)
+			}
+		}
+		return object
+	case []interface{}:
+		array := make([]interface{}, len(value))
+		for i, child := range value {
+			array[i] = stripValue(child, location+"/"+strconv.Itoa(i), stripped)
+		}
+		return array
+	default:
+		return value
+	}
+}
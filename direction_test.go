@@ -0,0 +1,124 @@
+package jsonschema
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/test-go/testify/assert"
+)
+
+const accountSchemaJSON = `{
+	"$defs": {
+		"secret": {"type": "string", "writeOnly": true}
+	},
+	"type": "object",
+	"properties": {
+		"id": {"type": "string", "readOnly": true},
+		"name": {"type": "string"},
+		"password": {"$ref": "#/$defs/secret"},
+		"keys": {"type": "array", "items": {"properties": {"value": {"$ref": "#/$defs/secret"}}}}
+	},
+	"required": ["id", "name", "password"]
+}`
+
+func TestValidateDirection(t *testing.T) {
+	schema, err := NewCompiler().Compile([]byte(accountSchemaJSON))
+	assert.NoError(t, err)
+
+	request := map[string]interface{}{"name": "ada", "password": "s3cret"}
+	response := map[string]interface{}{"id": "1", "name": "ada"}
+
+	// Without a direction, readOnly and writeOnly only annotate the instance.
+	assert.False(t, schema.Validate(request).IsValid())
+	assert.True(t, schema.Validate(map[string]interface{}{"id": "1", "name": "ada", "password": "s3cret"}).IsValid())
+
+	// The required properties the direction forbids may be missing.
+	assert.True(t, schema.ValidateRequest(request).IsValid())
+	assert.True(t, schema.ValidateResponse(response).IsValid())
+	assert.False(t, schema.ValidateResponse(map[string]interface{}{"id": "1"}).IsValid())
+
+	result := schema.ValidateRequest(map[string]interface{}{"id": "1", "name": "ada", "password": "s3cret"})
+	assert.False(t, result.IsValid())
+	violations := result.Violations()
+	if assert.Len(t, violations, 1) {
+		assert.Equal(t, "/id", violations[0].InstancePath)
+		assert.Equal(t, "readOnly", violations[0].Keyword)
+		assert.Equal(t, "read_only_value", violations[0].Code)
+	}
+
+	ctx := WithDirection(context.Background(), Response)
+	result = schema.ValidateContext(ctx, map[string]interface{}{
+		"id":   "1",
+		"name": "ada",
+		"keys": []interface{}{map[string]interface{}{"value": "k"}},
+	})
+	assert.False(t, result.IsValid())
+	violations = result.Violations()
+	if assert.Len(t, violations, 1) {
+		assert.Equal(t, "/keys/0/value", violations[0].InstancePath)
+		assert.Equal(t, "/properties/keys/items/properties/value/$ref", violations[0].SchemaPath)
+		assert.Equal(t, "write_only_value", violations[0].Code)
+	}
+
+	result = schema.ValidateContext(WithFailFast(ctx), map[string]interface{}{"id": "1", "name": "ada", "password": "s3cret"})
+	assert.False(t, result.IsValid())
+}
+
+func TestStrip(t *testing.T) {
+	schema, err := NewCompiler().Compile([]byte(accountSchemaJSON))
+	assert.NoError(t, err)
+
+	instance := map[string]interface{}{
+		"id":       "1",
+		"name":     "ada",
+		"password": "s3cret",
+		"keys":     []interface{}{map[string]interface{}{"value": "k", "label": "main"}},
+	}
+
+	stripped, err := schema.Strip(Request, instance)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{
+		"name":     "ada",
+		"password": "s3cret",
+		"keys":     []interface{}{map[string]interface{}{"value": "k", "label": "main"}},
+	}, stripped)
+
+	stripped, err = schema.Strip(Response, instance)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{
+		"id":   "1",
+		"name": "ada",
+		"keys": []interface{}{map[string]interface{}{"label": "main"}},
+	}, stripped)
+	assert.True(t, schema.ValidateResponse(stripped).IsValid())
+
+	// The instance is left unchanged, and invalid values are stripped too.
+	assert.Contains(t, instance, "password")
+	stripped, err = schema.Strip(Response, map[string]interface{}{"password": 42})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{}, stripped)
+
+	type account struct {
+		ID       string `json:"id"`
+		Password string `json:"password"`
+	}
+	stripped, err = schema.Strip(Response, account{ID: "1", Password: "s3cret"})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"id": "1"}, stripped)
+
+	_, err = schema.Strip(Request, make(chan int))
+	assert.Error(t, err)
+
+	// The result is a copy even when nothing is stripped.
+	name := map[string]interface{}{"name": "ada"}
+	stripped, err = schema.Strip(Request, name)
+	assert.NoError(t, err)
+	stripped.(map[string]interface{})["name"] = "bob"
+	assert.Equal(t, "ada", name["name"])
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = schema.StripContext(ctx, Request, instance)
+	assert.True(t, errors.Is(err, context.Canceled), "Expected context.Canceled, got %v", err)
+}
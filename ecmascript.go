@@ -0,0 +1,180 @@
+package jsonschema
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/dlclark/regexp2"
+)
+
+// ECMAScriptRegexEngine compiles regular expressions with ECMA-262 semantics, as the JSON Schema
+// specification requires: \d, \w and their negations only match ASCII characters, $ does not match
+// before a trailing newline, and lookarounds and backreferences are supported. Patterns are treated
+// as if the "u" flag were set, so Unicode property escapes such as \p{Letter} are available and
+// escapes that ECMA-262 does not define, such as \a, are rejected.
+type ECMAScriptRegexEngine struct {
+	// MatchTimeout bounds the time spent matching a single string, protecting against catastrophic
+	// backtracking. A string that cannot be matched in time is reported as not matching.
+	// Zero means no limit.
+	MatchTimeout time.Duration
+}
+
+// Compile compiles pattern using the ECMA-262 syntax.
+func (e ECMAScriptRegexEngine) Compile(pattern string) (Regexp, error) {
+	translated, err := translateECMAScriptPattern(pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	compiled, err := regexp2.Compile(translated, regexp2.ECMAScript|regexp2.Unicode)
+	if err != nil {
+		return nil, err
+	}
+	if e.MatchTimeout > 0 {
+		compiled.MatchTimeout = e.MatchTimeout
+	}
+
+	return &ecmaScriptRegexp{regexp: compiled}, nil
+}
+
+// ecmaScriptRegexp adapts a regexp2 expression to the Regexp interface.
+type ecmaScriptRegexp struct {
+	regexp *regexp2.Regexp
+}
+
+// MatchString reports whether s contains any match of the expression.
+func (r *ecmaScriptRegexp) MatchString(s string) bool {
+	matched, err := r.regexp.MatchString(s)
+	return err == nil && matched
+}
+
+// ecmaScriptEscapes lists the letters that may follow a backslash in an ECMA-262 pattern.
+const ecmaScriptEscapes = "bBcdDfknprPsStuvwWx"
+
+// translateECMAScriptPattern rewrites the parts of an ECMA-262 pattern that regexp2 does not
+// understand natively: Unicode property names are mapped to the names regexp2 accepts, and
+// escapes of letters that ECMA-262 does not define are reported as errors.
+func translateECMAScriptPattern(pattern string) (string, error) {
+	var b strings.Builder
+	b.Grow(len(pattern))
+
+	for i := 0; i < len(pattern); i++ {
+		c := pattern[i]
+		if c != '\\' || i+1 == len(pattern) {
+			b.WriteByte(c)
+			continue
+		}
+
+		i++
+		escape := pattern[i]
+		switch {
+		case escape == 'p' || escape == 'P':
+			end := strings.IndexByte(pattern[i:], '}')
+			if i+1 == len(pattern) || pattern[i+1] != '{' || end < 0 {
+				return "", fmt.Errorf("invalid property escape at offset %d", i-1)
+			}
+			name, err := translateUnicodeProperty(pattern[i+2 : i+end])
+			if err != nil {
+				return "", err
+			}
+			b.WriteByte('\\')
+			b.WriteByte(escape)
+			b.WriteString("{" + name + "}")
+			i += end
+		case isASCIILetter(escape) && !strings.ContainsRune(ecmaScriptEscapes, rune(escape)):
+			return "", fmt.Errorf("invalid escape \\%c at offset %d", escape, i-1)
+		default:
+			b.WriteByte('\\')
+			b.WriteByte(escape)
+		}
+	}
+
+	return b.String(), nil
+}
+
+// translateUnicodeProperty maps the name of an ECMA-262 Unicode property escape, such as
+// "Letter", "gc=Lu" or "Script=Greek", to the name understood by regexp2.
+func translateUnicodeProperty(name string) (string, error) {
+	key, value, hasKey := strings.Cut(name, "=")
+	if !hasKey {
+		if category, ok := generalCategories[name]; ok {
+			return category, nil
+		}
+		// Binary properties and scripts share their names with Go's unicode tables.
+		return name, nil
+	}
+
+	switch key {
+	case "General_Category", "gc":
+		if category, ok := generalCategories[value]; ok {
+			return category, nil
+		}
+		return "", fmt.Errorf("unknown general category %q", value)
+	case "Script", "sc", "Script_Extensions", "scx":
+		// Script extensions are approximated by the script itself.
+		return value, nil
+	default:
+		return "", fmt.Errorf("unknown Unicode property %q", key)
+	}
+}
+
+// isASCIILetter tells whether c is an ASCII letter.
+func isASCIILetter(c byte) bool {
+	return 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z'
+}
+
+// generalCategories maps the names and aliases of the Unicode general categories accepted by
+// ECMA-262 to their short names.
+//
+// Reference: https://www.unicode.org/Public/UCD/latest/ucd/PropertyValueAliases.txt
+var generalCategories = func() map[string]string {
+	aliases := [][]string{
+		{"C", "Other"},
+		{"Cc", "Control", "cntrl"},
+		{"Cf", "Format"},
+		{"Cn", "Unassigned"},
+		{"Co", "Private_Use"},
+		{"Cs", "Surrogate"},
+		{"L", "Letter"},
+		{"LC", "Cased_Letter"},
+		{"Ll", "Lowercase_Letter"},
+		{"Lm", "Modifier_Letter"},
+		{"Lo", "Other_Letter"},
+		{"Lt", "Titlecase_Letter"},
+		{"Lu", "Uppercase_Letter"},
+		{"M", "Mark", "Combining_Mark"},
+		{"Mc", "Spacing_Mark"},
+		{"Me", "Enclosing_Mark"},
+		{"Mn", "Nonspacing_Mark"},
+		{"N", "Number"},
+		{"Nd", "Decimal_Number", "digit"},
+		{"Nl", "Letter_Number"},
+		{"No", "Other_Number"},
+		{"P", "Punctuation", "punct"},
+		{"Pc", "Connector_Punctuation"},
+		{"Pd", "Dash_Punctuation"},
+		{"Pe", "Close_Punctuation"},
+		{"Pf", "Final_Punctuation"},
+		{"Pi", "Initial_Punctuation"},
+		{"Po", "Other_Punctuation"},
+		{"Ps", "Open_Punctuation"},
+		{"S", "Symbol"},
+		{"Sc", "Currency_Symbol"},
+		{"Sk", "Modifier_Symbol"},
+		{"Sm", "Math_Symbol"},
+		{"So", "Other_Symbol"},
+		{"Z", "Separator"},
+		{"Zl", "Line_Separator"},
+		{"Zp", "Paragraph_Separator"},
+		{"Zs", "Space_Separator"},
+	}
+
+	categories := make(map[string]string)
+	for _, names := range aliases {
+		for _, name := range names {
+			categories[name] = names[0]
+		}
+	}
+	return categories
+}()
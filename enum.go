@@ -1,7 +1,5 @@
 package jsonschema
 
-import "reflect"
-
 // EvaluateEnum checks if the data's value matches one of the enumerated values specified in the schema.
 // According to the JSON Schema Draft 2020-12:
 //   - The value of the "enum" keyword must be an array.
@@ -11,14 +9,13 @@ import "reflect"
 //
 // This method ensures that the data instance conforms to the enumerated values defined in the schema.
 // If the instance does not match any of the enumerated values, it returns a EvaluationError detailing the allowed values.
+// The enumerated values are looked up in enum, the set of the values compiled with the schema.
 //
 // Reference: https://json-schema.org/draft/2020-12/json-schema-validation#name-enum
-func evaluateEnum(schema *Schema, instance interface{}) *EvaluationError {
+func evaluateEnum(schema *Schema, instance interface{}, enum jsonSet) *EvaluationError {
 	if schema.Enum != nil && len(schema.Enum) > 0 {
-		for _, enumValue := range schema.Enum {
-			if reflect.DeepEqual(instance, enumValue) {
-				return nil // Match found.
-			}
+		if enum.contains(instance) {
+			return nil // Match found.
 		}
 		// No match found.
 		return NewEvaluationError("enum", "value_not_in_enum", "Value should match one of the values specified by the enum")
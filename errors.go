@@ -47,11 +47,44 @@ var ErrSegmentNotFoundForJSONPointer = errors.New("segment not found in the sche
 // ErrFailedToResolveReference is returned when a reference cannot be resolved.
 var ErrFailedToResolveReference = errors.New("failed to resolve reference")
 
+// ErrFailedToResolveAnchor is returned when a reference names an anchor that is not defined.
+var ErrFailedToResolveAnchor = errors.New("failed to resolve anchor")
+
 // ErrFailedToResolveDefinitions is returned when definitions in $defs cannot be resolved.
 var ErrFailedToResolveDefinitions = errors.New("failed to resolve definitions in $defs")
 
 // ErrFailedToResolveItems is returned when items in an array schema cannot be resolved.
 var ErrFailedToResolveItems = errors.New("failed to resolve items")
 
+// ErrInvalidRegexPattern is returned when a pattern or patternProperties regular expression cannot be compiled.
+var ErrInvalidRegexPattern = errors.New("invalid regular expression pattern")
+
+// ErrInvalidSchema is returned when a schema does not conform to the meta-schema.
+var ErrInvalidSchema = errors.New("schema does not conform to the meta-schema")
+
+// ErrUnsupportedVocabulary is returned when a meta-schema requires a vocabulary the compiler does not support.
+var ErrUnsupportedVocabulary = errors.New("unsupported required vocabulary")
+
+// ErrInvalidKeywordValue is returned when a custom keyword rejects its value in a schema.
+var ErrInvalidKeywordValue = errors.New("invalid keyword value")
+
+// ErrStandardKeyword is the panic value of RegisterKeyword for the name of a standard keyword.
+var ErrStandardKeyword = errors.New("custom keyword name is taken by a standard keyword")
+
+// ErrUnsupportedInstanceType is returned when a value of a Go type that has no JSON representation is validated.
+var ErrUnsupportedInstanceType = errors.New("unsupported instance type")
+
+// ErrInstanceMarshalError is returned when a validated value fails to marshal itself.
+var ErrInstanceMarshalError = errors.New("failed to marshal instance")
+
+// ErrInvalidJSON is returned when a JSON document passed for validation is malformed.
+var ErrInvalidJSON = errors.New("invalid JSON")
+
+// ErrDuplicateKey is returned when an object in a JSON document passed for validation repeats a key.
+var ErrDuplicateKey = errors.New("duplicate object key")
+
+// ErrInvalidInstance is returned when an instance does not conform to a schema.
+var ErrInvalidInstance = errors.New("instance does not conform to the schema")
+
 // ErrInvalidJSONSchemaType is returned when the JSON schema type is invalid.
 var ErrInvalidJSONSchemaType = errors.New("invalid JSON schema type")
@@ -1,41 +1,78 @@
 package jsonschema
 
+// UnknownFormatPolicy selects how a Compiler treats formats it has no validator for.
+type UnknownFormatPolicy int
+
+const (
+	// UnknownFormatAnnotate accepts instances of unknown formats and records the name of the
+	// format as an "unknownFormat" annotation of the result. It is the default policy.
+	UnknownFormatAnnotate UnknownFormatPolicy = iota
+	// UnknownFormatIgnore accepts instances of unknown formats silently.
+	UnknownFormatIgnore
+	// UnknownFormatAssert rejects every instance of an unknown format.
+	UnknownFormatAssert
+)
+
 // EvaluateFormat checks if the data conforms to the format specified in the schema.
 // According to the JSON Schema Draft 2020-12:
 //   - The "format" keyword defines the data format expected for a value.
 //   - The format must be a string that names a specific format which the value should conform to.
-//   - The function uses the `Formats` map to find the appropriate function to validate the format.
-//   - If the format is not supported or not found, it may fall back to a no-op validation depending on configuration.
+//   - The function looks up the validator of the format among the formats registered on the compiler,
+//     the deprecated `Formats` map and the built-in formats, in that order.
+//   - Formats are asserted when the compiler enables format assertion or the schema's dialect includes the
+//     format-assertion vocabulary, and ignored when the dialect includes neither format vocabulary.
+//   - Unknown formats are handled according to the compiler's UnknownFormatPolicy, except that the
+//     format-assertion vocabulary requires them to fail.
 //
 // This method ensures that data matches the expected format as specified in the schema.
 // It handles formats as annotations by default, but can assert format validation if configured.
+// It reports whether the unknown format of the schema should be recorded as an annotation.
 //
 // Reference: https://json-schema.org/draft/2020-12/json-schema-validation#name-format
-func evaluateFormat(schema *Schema, value interface{}) *EvaluationError {
+func evaluateFormat(schema *Schema, value interface{}) (bool, *EvaluationError) {
 	if schema.Format == nil {
-		return nil // No format to validate against.
+		return false, nil // No format to validate against.
+	}
+
+	formatAssertion := schema.hasVocabulary(VocabularyFormatAssertion)
+	assertFormat := formatAssertion || schema.compiler != nil && schema.compiler.AssertFormat
+	if !assertFormat && !schema.hasVocabulary(VocabularyFormatAnnotation) {
+		return false, nil // The format keyword is not part of the schema's dialect.
 	}
 
-	formatFunc, exists := Formats[*schema.Format]
+	formatFunc, exists := schema.compiler.getFormat(*schema.Format)
 	if !exists {
-		if schema.compiler != nil && schema.compiler.AssertFormat {
-			// If the format is not recognized, the behavior depends on the implementation
-			// configurations: it can ignore the unknown format (annotation behavior) or
-			// consider it an error (assertion behavior).
-			return NewEvaluationError("format", "unsupported_format", "Format {format} is not supported", map[string]interface{}{
+		policy := UnknownFormatAnnotate
+		if schema.compiler != nil {
+			policy = schema.compiler.UnknownFormats
+		}
+
+		if formatAssertion || policy == UnknownFormatAssert {
+			return false, NewEvaluationError("format", "unsupported_format", "Format {format} is not supported", map[string]interface{}{
 				"format": *schema.Format,
 			})
 		}
+		return policy == UnknownFormatAnnotate, nil
 	}
 
 	// Execute the format validation function
-	if !formatFunc(value) {
-		if schema.compiler != nil && schema.compiler.AssertFormat {
-			return NewEvaluationError("format", "format_mismatch", "Value does not match format {format}", map[string]interface{}{
-				"format": *schema.Format,
-			})
-		}
+	if assertFormat && !formatFunc(value) {
+		return false, NewEvaluationError("format", "format_mismatch", "Value does not match format {format}", map[string]interface{}{
+			"format": *schema.Format,
+		})
 	}
 
-	return nil
+	return false, nil
+}
+
+// formatAppliesTo tells whether an instance has one of the JSON types a format validator checks.
+// The "number" type includes integers.
+func formatAppliesTo(instance interface{}, types []string) bool {
+	instanceType := getDataType(instance)
+	for _, t := range types {
+		if t == instanceType || t == "number" && instanceType == "integer" {
+			return true
+		}
+	}
+	return false
 }
@@ -0,0 +1,172 @@
+package jsonschema
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/goccy/go-json"
+	"github.com/test-go/testify/assert"
+)
+
+func TestRegisterFormat(t *testing.T) {
+	upper := func(v interface{}) bool {
+		s, ok := v.(string)
+		return ok && s == strings.ToUpper(s)
+	}
+	lower := func(v interface{}) bool {
+		s, ok := v.(string)
+		return ok && s == strings.ToLower(s)
+	}
+
+	t.Run("per compiler", func(t *testing.T) {
+		first := NewCompiler().SetAssertFormat(true).RegisterFormat("case", upper)
+		second := NewCompiler().SetAssertFormat(true).RegisterFormat("case", lower)
+
+		firstSchema, err := first.Compile([]byte(`{"format": "case"}`))
+		assert.NoError(t, err)
+		secondSchema, err := second.Compile([]byte(`{"format": "case"}`))
+		assert.NoError(t, err)
+
+		assert.True(t, firstSchema.Validate("ABC").IsValid())
+		assert.False(t, firstSchema.Validate("abc").IsValid())
+		assert.True(t, secondSchema.Validate("abc").IsValid())
+		assert.False(t, secondSchema.Validate("ABC").IsValid())
+
+		_, registered := Formats["case"]
+		assert.False(t, registered, "Expected the global Formats to be left untouched")
+	})
+
+	t.Run("typed validator", func(t *testing.T) {
+		compiler := NewCompiler().SetAssertFormat(true).RegisterFormat("case", upper, "string")
+
+		schema, err := compiler.Compile([]byte(`{"format": "case"}`))
+		assert.NoError(t, err)
+		assert.True(t, schema.Validate(42).IsValid(), "Expected non-string instances to be ignored")
+		assert.False(t, schema.Validate("abc").IsValid())
+
+		compiler.RegisterFormat("even", func(v interface{}) bool {
+			n, ok := v.(float64)
+			return ok && int64(n)%2 == 0
+		}, "number")
+
+		schema, err = compiler.Compile([]byte(`{"format": "even"}`))
+		assert.NoError(t, err)
+		assert.True(t, schema.Validate(float64(2)).IsValid())
+		assert.False(t, schema.Validate(float64(3)).IsValid())
+		assert.True(t, schema.Validate("3").IsValid())
+	})
+
+	t.Run("built-in fallback", func(t *testing.T) {
+		compiler := NewCompiler().SetAssertFormat(true)
+
+		schema, err := compiler.Compile([]byte(`{"format": "ipv4"}`))
+		assert.NoError(t, err)
+		assert.False(t, schema.Validate("not-an-ipv4").IsValid())
+
+		compiler.RegisterFormat("ipv4", func(interface{}) bool { return true })
+		schema, err = compiler.Compile([]byte(`{"format": "ipv4"}`))
+		assert.NoError(t, err)
+		assert.True(t, schema.Validate("not-an-ipv4").IsValid(), "Expected the registered format to override the built-in one")
+	})
+
+	t.Run("deprecated global formats", func(t *testing.T) {
+		Formats["global-case"] = upper
+		defer delete(Formats, "global-case")
+
+		schema, err := NewCompiler().SetAssertFormat(true).Compile([]byte(`{"format": "global-case"}`))
+		assert.NoError(t, err)
+		assert.True(t, schema.Validate("ABC").IsValid())
+		assert.False(t, schema.Validate("abc").IsValid())
+	})
+}
+
+func TestUnknownFormatPolicy(t *testing.T) {
+	schemaJSON := []byte(`{"format": "no-such-format"}`)
+
+	for _, assertFormat := range []bool{false, true} {
+		t.Run("annotate", func(t *testing.T) {
+			schema, err := NewCompiler().SetAssertFormat(assertFormat).Compile(schemaJSON)
+			assert.NoError(t, err)
+
+			result := schema.Validate("value")
+			assert.True(t, result.IsValid())
+			assert.Equal(t, "no-such-format", result.Annotations["unknownFormat"])
+
+			result = schema.ValidateFast("value")
+			assert.True(t, result.IsValid())
+			assert.NotContains(t, result.Annotations, "unknownFormat", "Expected no annotation in fail-fast mode")
+		})
+
+		t.Run("ignore", func(t *testing.T) {
+			compiler := NewCompiler().SetAssertFormat(assertFormat).SetUnknownFormatPolicy(UnknownFormatIgnore)
+			schema, err := compiler.Compile(schemaJSON)
+			assert.NoError(t, err)
+
+			result := schema.Validate("value")
+			assert.True(t, result.IsValid())
+			assert.NotContains(t, result.Annotations, "unknownFormat")
+		})
+
+		t.Run("assert", func(t *testing.T) {
+			compiler := NewCompiler().SetAssertFormat(assertFormat).SetUnknownFormatPolicy(UnknownFormatAssert)
+			schema, err := compiler.Compile(schemaJSON)
+			assert.NoError(t, err)
+
+			result := schema.Validate("value")
+			assert.False(t, result.IsValid())
+			assert.Contains(t, result.Errors, "format")
+		})
+	}
+
+	// The builtin "unknown" format accepts any value, whatever the policy.
+	schema, err := NewCompiler().SetUnknownFormatPolicy(UnknownFormatAssert).Compile([]byte(`{"format": "unknown"}`))
+	assert.NoError(t, err)
+	result := schema.Validate("value")
+	assert.True(t, result.IsValid())
+	assert.NotContains(t, result.Annotations, "unknownFormat")
+}
+
+func TestFormats(t *testing.T) {
+	tests := []struct {
+		format  string
+		valid   []interface{}
+		invalid []interface{}
+	}{
+		{"iri", []interface{}{"http://ƒøø.ßår/?∂éœ=πîx"}, []interface{}{"/âππ"}},
+		{"uri", []interface{}{"http://example.com/a%C3%A9"}, []interface{}{"http://ƒøø.ßår/"}},
+		{"iri-reference", []interface{}{"/âππ"}, []interface{}{`\\WINDOWS\filëßåré`}},
+		{"uri-reference", []interface{}{"/abc"}, []interface{}{"/âππ"}},
+		{"idn-hostname", []interface{}{"실례.테스트", "xn--ihqwcrb4cv8a8dqg056pqjye"}, []interface{}{"〮실례.테스트", "xn--X"}},
+		{"idn-email", []interface{}{"실례@실례.테스트"}, []interface{}{"2962"}},
+		{"byte", []interface{}{"aGVsbG8=", ""}, []interface{}{"aGVsbG8", "not base64!"}},
+		{"int32", []interface{}{float64(2147483647), float64(-2147483648), "text"}, []interface{}{float64(2147483648), 1.5}},
+		{"int64", []interface{}{float64(1 << 53), json.Number("-9223372036854775808")}, []interface{}{json.Number("9223372036854775808"), 0.5}},
+		{"float", []interface{}{3.4e38, 1.5}, []interface{}{3.5e38, -1e39}},
+		{"double", []interface{}{1.7e308, json.Number("-1.7e308")}, []interface{}{json.Number("1.8e308")}},
+		{"password", []interface{}{"secret"}, nil},
+		{"semver", []interface{}{"1.0.0", "1.2.3-alpha.1+build.5"}, []interface{}{"1.0", "01.0.0", "1.0.0-"}},
+		{"ulid", []interface{}{"01ARZ3NDEKTSV4RRFFQ69G5FAV", "7zzzzzzzzzzzzzzzzzzzzzzzzz"}, []interface{}{"81ARZ3NDEKTSV4RRFFQ69G5FAV", "01ARZ3NDEKTSV4RRFFQ69G5FAI", "01ARZ3NDEK"}},
+		{"mac", []interface{}{"00:1a:2b:3c:4d:5e", "00-1A-2B-3C-4D-5E", "0000.5e00.5301"}, []interface{}{"00:1a:2b:3c:4d", "00:1a:2b:3c:4d:zz"}},
+		{"cidr", []interface{}{"192.168.0.0/16", "2001:db8::/32"}, []interface{}{"192.168.0.0", "192.168.0.0/33"}},
+		{"e164", []interface{}{"+14155552671", "+442071838750"}, []interface{}{"14155552671", "+04155552671", "+1234567890123456"}},
+		{"iso4217", []interface{}{"EUR", "USD"}, []interface{}{"eur", "ABC", "EURO"}},
+		{"iso3166", []interface{}{"FR", "US"}, []interface{}{"fr", "ZZ", "FRA"}},
+		{"bcp47", []interface{}{"en", "en-US", "zh-Hant-TW", "de-CH-1996"}, []interface{}{"en--US", "abcdefghi"}},
+		{"json", []interface{}{`{"a": [1, 2]}`, "null"}, []interface{}{`{"a": }`, ""}},
+	}
+
+	compiler := NewCompiler().SetAssertFormat(true)
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			schema, err := compiler.Compile([]byte(`{"format": "` + tt.format + `"}`))
+			assert.NoError(t, err)
+
+			for _, value := range tt.valid {
+				assert.True(t, schema.Validate(value).IsValid(), "Expected %v to be a valid %s", value, tt.format)
+			}
+			for _, value := range tt.invalid {
+				assert.False(t, schema.Validate(value).IsValid(), "Expected %v to be an invalid %s", value, tt.format)
+			}
+		})
+	}
+}
@@ -9,37 +9,72 @@ import (
 	"strconv"
 	"strings"
 	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/net/idna"
 )
 
-// Formats is a registry of functions, which know how to validate
-// a specific format.
-//
-// New Formats can be registered by adding to this map. Key is format name,
-// value is function that knows how to validate that format.
-var Formats = map[string]func(interface{}) bool{
+// builtinFormats holds the format validators every Compiler knows. It is never modified.
+var builtinFormats = map[string]func(interface{}) bool{
 	"date-time":             IsDateTime,
 	"date":                  IsDate,
 	"time":                  IsTime,
 	"duration":              IsDuration,
 	"period":                IsPeriod,
 	"hostname":              IsHostname,
+	"idn-hostname":          IsIDNHostname,
 	"email":                 IsEmail,
+	"idn-email":             IsIDNEmail,
 	"ip-address":            IsIPV4,
 	"ipv4":                  IsIPV4,
 	"ipv6":                  IsIPV6,
 	"uri":                   IsURI,
-	"iri":                   IsURI,
+	"iri":                   IsIRI,
 	"uri-reference":         IsURIReference,
 	"uriref":                IsURIReference,
-	"iri-reference":         IsURIReference,
+	"iri-reference":         IsIRIReference,
 	"uri-template":          IsURITemplate,
 	"json-pointer":          IsJSONPointer,
 	"relative-json-pointer": IsRelativeJSONPointer,
 	"uuid":                  IsUUID,
 	"regex":                 IsRegex,
 	"unknown":               func(interface{}) bool { return true },
+
+	// Formats defined by OpenAPI, see https://spec.openapis.org/oas/v3.1.0#data-types
+	"byte":     IsByte,
+	"int32":    IsInt32,
+	"int64":    IsInt64,
+	"float":    IsFloat,
+	"double":   IsDouble,
+	"password": func(interface{}) bool { return true },
+
+	// Other common formats
+	"semver":  IsSemver,
+	"ulid":    IsULID,
+	"mac":     IsMAC,
+	"cidr":    IsCIDR,
+	"e164":    IsE164,
+	"iso4217": IsISO4217,
+	"iso3166": IsISO3166,
+	"bcp47":   IsBCP47,
+	"json":    IsJSON,
 }
 
+// Formats is a registry of functions, which know how to validate
+// a specific format. It starts out with the built-in formats, and is
+// consulted by every Compiler after the formats registered on it.
+//
+// Deprecated: Formats is shared by all compilers and is not safe to modify
+// while schemas are validated. Use Compiler.RegisterFormat instead.
+var Formats = func() map[string]func(interface{}) bool {
+	formats := make(map[string]func(interface{}) bool, len(builtinFormats))
+	for name, fn := range builtinFormats {
+		formats[name] = fn
+	}
+	return formats
+}()
+
 // IsDateTime tells whether given string is a valid date representation
 // as defined by RFC 3339, section 5.6.
 //
@@ -305,6 +340,82 @@ func IsHostname(v interface{}) bool {
 	return true
 }
 
+// IsIDNHostname tells whether given string is a valid representation for an
+// internationalized Internet host name, as defined by RFC 5890 section 2.3.2.3:
+// every label must be a valid IDNA2008 A-label or U-label, including the
+// contextual rules of RFC 5892 Appendix A and the Bidi rule of RFC 5893.
+func IsIDNHostname(v interface{}) bool {
+	s, ok := v.(string)
+	if !ok {
+		return true
+	}
+
+	ascii, err := idna.Registration.ToASCII(strings.TrimSuffix(s, "."))
+	if err != nil || !IsHostname(ascii) {
+		return false
+	}
+
+	decoded, err := idna.Registration.ToUnicode(ascii)
+	if err != nil {
+		return false
+	}
+	for _, label := range strings.Split(decoded, ".") {
+		if !isValidIDNLabel([]rune(label)) {
+			return false
+		}
+	}
+	return true
+}
+
+// isValidIDNLabel checks the code points of a U-label that IDNA2008 disallows
+// as exceptions, and the contextual rules for the CONTEXTO code points.
+//
+// see https://datatracker.ietf.org/doc/html/rfc5892#section-2.6 and
+// https://datatracker.ietf.org/doc/html/rfc5892#appendix-A, for details
+func isValidIDNLabel(label []rune) bool {
+	arabicIndic, extendedArabicIndic := false, false
+	for i, r := range label {
+		switch {
+		case r == 0x0640, r == 0x07FA, r == 0x302E, r == 0x302F, r >= 0x3031 && r <= 0x3035, r == 0x303B:
+			return false // DISALLOWED exceptions
+		case r == 0x00B7: // MIDDLE DOT
+			if i == 0 || i == len(label)-1 || label[i-1] != 'l' || label[i+1] != 'l' {
+				return false
+			}
+		case r == 0x0375: // GREEK LOWER NUMERAL SIGN (KERAIA)
+			if i == len(label)-1 || !unicode.Is(unicode.Greek, label[i+1]) {
+				return false
+			}
+		case r == 0x05F3, r == 0x05F4: // HEBREW PUNCTUATION GERESH and GERSHAYIM
+			if i == 0 || !unicode.Is(unicode.Hebrew, label[i-1]) {
+				return false
+			}
+		case r == 0x30FB: // KATAKANA MIDDLE DOT
+			if !containsRune(label, func(r rune) bool {
+				return r != 0x30FB && (unicode.In(r, unicode.Hiragana, unicode.Katakana, unicode.Han))
+			}) {
+				return false
+			}
+		case r >= 0x0660 && r <= 0x0669:
+			arabicIndic = true
+		case r >= 0x06F0 && r <= 0x06F9:
+			extendedArabicIndic = true
+		}
+	}
+	// ARABIC-INDIC DIGITS and EXTENDED ARABIC-INDIC DIGITS cannot be mixed
+	return !arabicIndic || !extendedArabicIndic
+}
+
+// containsRune tells whether any rune of s satisfies f.
+func containsRune(s []rune, f func(rune) bool) bool {
+	for _, r := range s {
+		if f(r) {
+			return true
+		}
+	}
+	return false
+}
+
 // IsEmail tells whether given string is a valid Internet email address
 // as defined by RFC 5322, section 3.4.1.
 //
@@ -314,6 +425,23 @@ func IsEmail(v interface{}) bool {
 	if !ok {
 		return true
 	}
+	return isEmail(s, IsHostname)
+}
+
+// IsIDNEmail tells whether given string is a valid internationalized email address
+// as defined by RFC 6531, section 3.3, whose local part and domain may contain
+// Unicode characters.
+func IsIDNEmail(v interface{}) bool {
+	s, ok := v.(string)
+	if !ok {
+		return true
+	}
+	return isEmail(s, IsIDNHostname)
+}
+
+// isEmail checks an email address whose domain, unless it is an IP address, is
+// checked by isHostname.
+func isEmail(s string, isHostname func(interface{}) bool) bool {
 	// entire email address to be no more than 254 characters long
 	if len(s) > 254 {
 		return false
@@ -342,7 +470,7 @@ func IsEmail(v interface{}) bool {
 	}
 
 	// domain must match the requirements for a hostname
-	if !IsHostname(domain) {
+	if !isHostname(domain) {
 		return false
 	}
 
@@ -395,10 +523,35 @@ func IsURI(v interface{}) bool {
 	if !ok {
 		return true
 	}
+	return isASCII(s) && isIRI(s)
+}
+
+// IsIRI tells whether given string is valid IRI, according to RFC 3987.
+// An IRI is a URI that may also contain Unicode characters.
+func IsIRI(v interface{}) bool {
+	s, ok := v.(string)
+	if !ok {
+		return true
+	}
+	return isIRI(s)
+}
+
+// isIRI tells whether s is an absolute IRI.
+func isIRI(s string) bool {
 	u, err := urlParse(s)
 	return err == nil && u.IsAbs()
 }
 
+// isASCII tells whether s only contains ASCII characters.
+func isASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= utf8.RuneSelf {
+			return false
+		}
+	}
+	return true
+}
+
 func urlParse(s string) (*url.URL, error) {
 	u, err := url.Parse(s)
 	if err != nil {
@@ -425,6 +578,21 @@ func IsURIReference(v interface{}) bool {
 	if !ok {
 		return true
 	}
+	return isASCII(s) && isIRIReference(s)
+}
+
+// IsIRIReference tells whether given string is a valid IRI Reference
+// (either an IRI or a relative-reference), according to RFC 3987.
+func IsIRIReference(v interface{}) bool {
+	s, ok := v.(string)
+	if !ok {
+		return true
+	}
+	return isIRIReference(s)
+}
+
+// isIRIReference tells whether s is an IRI or a relative reference.
+func isIRIReference(s string) bool {
 	_, err := urlParse(s)
 	return err == nil && !strings.Contains(s, `\`)
 }
@@ -557,7 +725,8 @@ func IsUUID(v interface{}) bool {
 	return len(s) == 0
 }
 
-// IsRegex tells whether given string is a valid regex pattern
+// IsRegex tells whether given string is a valid regex pattern in Go's RE2 syntax.
+// Schemas compiled by a Compiler check the regex format with the compiler's RegexEngine instead.
 func IsRegex(v interface{}) bool {
 	pattern, ok := v.(string)
 	if !ok {
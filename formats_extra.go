@@ -0,0 +1,224 @@
+package jsonschema
+
+import (
+	"encoding/base64"
+	"fmt"
+	"math"
+	"math/big"
+	"net"
+	"regexp"
+	"strconv"
+
+	"github.com/goccy/go-json"
+	"golang.org/x/text/currency"
+	"golang.org/x/text/language"
+)
+
+// IsByte tells whether given string is base64 encoded data, as used by the
+// OpenAPI "byte" format.
+//
+// see https://datatracker.ietf.org/doc/html/rfc4648#section-4, for details
+func IsByte(v interface{}) bool {
+	s, ok := v.(string)
+	if !ok {
+		return true
+	}
+	_, err := base64.StdEncoding.DecodeString(s)
+	return err == nil
+}
+
+// IsInt32 tells whether given number is an integer representable as a signed
+// 32-bit integer, as used by the OpenAPI "int32" format.
+func IsInt32(v interface{}) bool {
+	return isIntegerOfSize(v, 32)
+}
+
+// IsInt64 tells whether given number is an integer representable as a signed
+// 64-bit integer, as used by the OpenAPI "int64" format.
+func IsInt64(v interface{}) bool {
+	return isIntegerOfSize(v, 64)
+}
+
+// isIntegerOfSize tells whether v is an integer representable as a signed integer
+// of the given bit size. Instances that are not numbers are valid.
+func isIntegerOfSize(v interface{}, bitSize int) bool {
+	n, ok := formatNumber(v)
+	if !ok {
+		return true
+	}
+	if !n.IsInt() {
+		return false
+	}
+	limit := new(big.Int).Lsh(big.NewInt(1), uint(bitSize-1))
+	num := n.Num()
+	return num.Cmp(new(big.Int).Neg(limit)) >= 0 && num.Cmp(limit) < 0
+}
+
+// IsFloat tells whether given number is within the range of a single precision
+// floating point number, as used by the OpenAPI "float" format.
+func IsFloat(v interface{}) bool {
+	return isFloatOfSize(v, math.MaxFloat32)
+}
+
+// IsDouble tells whether given number is within the range of a double precision
+// floating point number, as used by the OpenAPI "double" format.
+func IsDouble(v interface{}) bool {
+	return isFloatOfSize(v, math.MaxFloat64)
+}
+
+// isFloatOfSize tells whether the magnitude of v does not exceed max. Instances
+// that are not numbers are valid.
+func isFloatOfSize(v interface{}, max float64) bool {
+	n, ok := formatNumber(v)
+	if !ok {
+		return true
+	}
+	return new(big.Rat).Abs(n).Cmp(new(big.Rat).SetFloat64(max)) <= 0
+}
+
+// formatNumber returns the exact value of a numeric instance.
+func formatNumber(v interface{}) (*big.Rat, bool) {
+	if dataType := getDataType(v); dataType != "number" && dataType != "integer" {
+		return nil, false
+	}
+	return new(big.Rat).SetString(fmt.Sprint(v))
+}
+
+// semverPattern is the regular expression suggested by the Semantic Versioning
+// specification.
+var semverPattern = regexp.MustCompile(`^(0|[1-9]\d*)\.(0|[1-9]\d*)\.(0|[1-9]\d*)` +
+	`(?:-((?:0|[1-9]\d*|\d*[a-zA-Z-][0-9a-zA-Z-]*)(?:\.(?:0|[1-9]\d*|\d*[a-zA-Z-][0-9a-zA-Z-]*))*))?` +
+	`(?:\+([0-9a-zA-Z-]+(?:\.[0-9a-zA-Z-]+)*))?$`)
+
+// IsSemver tells whether given string is a valid version as defined by
+// Semantic Versioning 2.0.0.
+//
+// see https://semver.org/spec/v2.0.0.html, for details
+func IsSemver(v interface{}) bool {
+	s, ok := v.(string)
+	if !ok {
+		return true
+	}
+	return semverPattern.MatchString(s)
+}
+
+// IsULID tells whether given string is a valid ULID: 26 characters of
+// Crockford's base32, whose first character does not overflow 128 bits.
+//
+// see https://github.com/ulid/spec, for details
+func IsULID(v interface{}) bool {
+	s, ok := v.(string)
+	if !ok {
+		return true
+	}
+	if len(s) != 26 || s[0] > '7' {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c >= 'a' && c <= 'z' {
+			c -= 'a' - 'A'
+		}
+		valid := (c >= '0' && c <= '9') || (c >= 'A' && c <= 'Z' && c != 'I' && c != 'L' && c != 'O' && c != 'U')
+		if !valid {
+			return false
+		}
+	}
+	return true
+}
+
+// IsMAC tells whether given string is a valid IEEE 802 MAC-48 or EUI-64 address,
+// written with colons, hyphens or dots as separators.
+func IsMAC(v interface{}) bool {
+	s, ok := v.(string)
+	if !ok {
+		return true
+	}
+	mac, err := net.ParseMAC(s)
+	return err == nil && (len(mac) == 6 || len(mac) == 8)
+}
+
+// IsCIDR tells whether given string is a valid IPv4 or IPv6 address prefix in
+// CIDR notation, as defined by RFC 4632 and RFC 4291.
+func IsCIDR(v interface{}) bool {
+	s, ok := v.(string)
+	if !ok {
+		return true
+	}
+	_, _, err := net.ParseCIDR(s)
+	return err == nil
+}
+
+// IsE164 tells whether given string is a phone number in the E.164 format: a plus
+// sign followed by up to 15 digits, the first of which is not zero.
+func IsE164(v interface{}) bool {
+	s, ok := v.(string)
+	if !ok {
+		return true
+	}
+	if len(s) < 3 || len(s) > 16 || s[0] != '+' || s[1] == '0' {
+		return false
+	}
+	_, err := strconv.ParseUint(s[1:], 10, 64)
+	return err == nil
+}
+
+// IsISO4217 tells whether given string is an ISO 4217 alphabetic currency code,
+// such as "EUR".
+func IsISO4217(v interface{}) bool {
+	s, ok := v.(string)
+	if !ok {
+		return true
+	}
+	if len(s) != 3 || !isUpperASCII(s) {
+		return false
+	}
+	_, err := currency.ParseISO(s)
+	return err == nil
+}
+
+// IsISO3166 tells whether given string is an ISO 3166-1 alpha-2 country code,
+// such as "FR".
+func IsISO3166(v interface{}) bool {
+	s, ok := v.(string)
+	if !ok {
+		return true
+	}
+	if len(s) != 2 || !isUpperASCII(s) {
+		return false
+	}
+	region, err := language.ParseRegion(s)
+	return err == nil && region.IsCountry()
+}
+
+// isUpperASCII tells whether s only contains upper case ASCII letters.
+func isUpperASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < 'A' || s[i] > 'Z' {
+			return false
+		}
+	}
+	return true
+}
+
+// IsBCP47 tells whether given string is a well-formed language tag as defined
+// by BCP 47, such as "en-US".
+//
+// see https://www.rfc-editor.org/info/bcp47, for details
+func IsBCP47(v interface{}) bool {
+	s, ok := v.(string)
+	if !ok {
+		return true
+	}
+	_, err := language.Parse(s)
+	return err == nil
+}
+
+// IsJSON tells whether given string is a valid JSON document.
+func IsJSON(v interface{}) bool {
+	s, ok := v.(string)
+	if !ok {
+		return true
+	}
+	return json.Valid([]byte(s))
+}
@@ -4,9 +4,11 @@ go 1.21.1
 
 require (
 	dario.cat/mergo v1.0.1
+	github.com/dlclark/regexp2 v1.7.0
 	github.com/goccy/go-json v0.10.3
 	github.com/kaptinlin/go-i18n v0.1.3
 	github.com/stretchr/testify v1.9.0
+	golang.org/x/net v0.26.0
 )
 
 require (
@@ -24,6 +26,6 @@ require (
 	github.com/pmezard/go-difflib v1.0.0 // indirect
 	github.com/test-go/testify v1.1.4
 	golang.org/x/sys v0.21.0 // indirect
-	golang.org/x/text v0.16.0 // indirect
+	golang.org/x/text v0.16.0
 	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
@@ -0,0 +1,587 @@
+package jsonschema
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/goccy/go-json"
+)
+
+// normalizeInstance converts a Go value into the generic representation of JSON values used
+// during evaluation: nil, bool, string, numbers, map[string]interface{} and []interface{}.
+// Structs, typed maps and slices, pointers and named types are walked with reflection following
+// the rules of encoding/json: struct fields are named by their json tag, fields tagged "-" are
+// skipped, empty fields tagged omitempty are omitted and embedded structs are flattened. Values
+// implementing json.Marshaler or encoding.TextMarshaler, such as time.Time and json.RawMessage,
+// are converted through their own encoding, whose numbers are kept exact as json.Number. Generic
+// trees are returned as is, without copying. A value that contains itself has no JSON
+// representation and fails with ErrUnsupportedInstanceType.
+func normalizeInstance(v interface{}) (interface{}, error) {
+	var n normalizer
+	normalized, _, err := n.normalizeGeneric(v)
+	return normalized, err
+}
+
+// startDetectingCyclesAfter is the nesting level past which the pointers, maps and slices being
+// normalized are tracked, like encoding/json does, so that common values are converted without cost.
+const startDetectingCyclesAfter = 1000
+
+// normalizer converts an instance, tracking the pointers, maps and slices it is within to detect
+// the values that contain themselves.
+type normalizer struct {
+	depth int
+	seen  map[visitKey]struct{}
+}
+
+// visitKey identifies a pointer, map or slice; slices sharing an array differ by their length.
+type visitKey struct {
+	ptr uintptr
+	len int
+}
+
+// enter records that the value v is being normalized, and fails if it already is.
+func (n *normalizer) enter(v reflect.Value) error {
+	n.depth++
+	if n.depth <= startDetectingCyclesAfter {
+		return nil
+	}
+
+	key := n.key(v)
+	if _, ok := n.seen[key]; ok {
+		return fmt.Errorf("%w: encountered a cycle via %s", ErrUnsupportedInstanceType, v.Type())
+	}
+	if n.seen == nil {
+		n.seen = make(map[visitKey]struct{})
+	}
+	n.seen[key] = struct{}{}
+	return nil
+}
+
+// leave records that the value v, entered last, is normalized.
+func (n *normalizer) leave(v reflect.Value) {
+	if n.depth > startDetectingCyclesAfter {
+		delete(n.seen, n.key(v))
+	}
+	n.depth--
+}
+
+func (n *normalizer) key(v reflect.Value) visitKey {
+	if v.Kind() == reflect.Slice {
+		return visitKey{ptr: v.Pointer(), len: v.Len()}
+	}
+	return visitKey{ptr: v.Pointer()}
+}
+
+// locatedError is an error found while normalizing the value at a JSON Pointer location of the instance.
+type locatedError struct {
+	location string
+	err      error
+}
+
+func (e *locatedError) Error() string {
+	return fmt.Sprintf("%v at %s", e.err, e.location)
+}
+
+func (e *locatedError) Unwrap() error {
+	return e.err
+}
+
+// within locates err, found while normalizing a member or an item, within its parent.
+func within(err error, segment string) error {
+	segment = "/" + escapeJSONPointerSegment(segment)
+	var located *locatedError
+	if errors.As(err, &located) {
+		located.location = segment + located.location
+		return located
+	}
+	return &locatedError{location: segment, err: err}
+}
+
+// normalizeGeneric normalizes a value and reports whether it had to be converted. Values of the
+// generic representation are returned as is: maps and slices are only walked into to find the
+// values of other types they hold, and only copied when one of them is converted.
+func (n *normalizer) normalizeGeneric(v interface{}) (interface{}, bool, error) {
+	switch value := v.(type) {
+	case map[string]interface{}:
+		if err := n.enter(reflect.ValueOf(v)); err != nil {
+			return nil, false, err
+		}
+		normalized, err := n.normalizeObject(value)
+		n.leave(reflect.ValueOf(v))
+		if err != nil {
+			return nil, false, err
+		}
+		if normalized == nil {
+			return v, false, nil
+		}
+		return normalized, true, nil
+	case []interface{}:
+		if err := n.enter(reflect.ValueOf(v)); err != nil {
+			return nil, false, err
+		}
+		normalized, err := n.normalizeItems(value)
+		n.leave(reflect.ValueOf(v))
+		if err != nil {
+			return nil, false, err
+		}
+		if normalized == nil {
+			return v, false, nil
+		}
+		return normalized, true, nil
+	}
+
+	if isJSONScalar(v) {
+		return v, false, nil
+	}
+	normalized, err := n.normalizeValue(reflect.ValueOf(v))
+	return normalized, true, err
+}
+
+// normalizeObject normalizes the members of a generic object. It returns a copy of the object if
+// one of them is converted, and nil otherwise.
+func (n *normalizer) normalizeObject(object map[string]interface{}) (map[string]interface{}, error) {
+	var normalized map[string]interface{}
+	for key, value := range object {
+		if isJSONScalar(value) {
+			continue
+		}
+
+		converted, changed, err := n.normalizeGeneric(value)
+		if err != nil {
+			return nil, within(err, key)
+		}
+		if !changed {
+			continue
+		}
+		if normalized == nil {
+			normalized = make(map[string]interface{}, len(object))
+			for k, original := range object {
+				normalized[k] = original
+			}
+		}
+		normalized[key] = converted
+	}
+	return normalized, nil
+}
+
+// normalizeItems normalizes the items of a generic array. It returns a copy of the array if one of
+// them is converted, and nil otherwise.
+func (n *normalizer) normalizeItems(array []interface{}) ([]interface{}, error) {
+	var normalized []interface{}
+	for i, value := range array {
+		if isJSONScalar(value) {
+			continue
+		}
+
+		converted, changed, err := n.normalizeGeneric(value)
+		if err != nil {
+			return nil, within(err, strconv.Itoa(i))
+		}
+		if !changed {
+			continue
+		}
+		if normalized == nil {
+			normalized = make([]interface{}, len(array))
+			copy(normalized, array)
+		}
+		normalized[i] = converted
+	}
+	return normalized, nil
+}
+
+// isJSONScalar tells whether a value is a scalar of the generic representation, left as is.
+func isJSONScalar(v interface{}) bool {
+	switch v.(type) {
+	case nil, bool, string, float64, float32, json.Number,
+		int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		return true
+	}
+	return false
+}
+
+var (
+	jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+	jsonNumberType    = reflect.TypeOf(json.Number(""))
+	objectType        = reflect.TypeOf(map[string]interface{}(nil))
+	arrayType         = reflect.TypeOf([]interface{}(nil))
+)
+
+// normalizeValue converts a value of any type into the generic representation of JSON values.
+func (n *normalizer) normalizeValue(v reflect.Value) (interface{}, error) {
+	if !v.IsValid() {
+		return nil, nil
+	}
+
+	if v.Type() == jsonNumberType {
+		return json.Number(v.String()), nil
+	}
+	if (v.Type() == objectType || v.Type() == arrayType) && v.CanInterface() {
+		// Generic objects and arrays held by other values are not copied either.
+		if v.IsNil() {
+			return nil, nil
+		}
+		normalized, _, err := n.normalizeGeneric(v.Interface())
+		return normalized, err
+	}
+	if converted, ok, err := marshalValue(v); ok {
+		return converted, err
+	}
+
+	switch v.Kind() {
+	case reflect.Interface, reflect.Pointer:
+		if v.IsNil() {
+			return nil, nil
+		}
+		if v.Kind() == reflect.Interface {
+			return n.normalizeValue(v.Elem())
+		}
+		if err := n.enter(v); err != nil {
+			return nil, err
+		}
+		defer n.leave(v)
+		return n.normalizeValue(v.Elem())
+	case reflect.Bool:
+		return v.Bool(), nil
+	case reflect.String:
+		return v.String(), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int(), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return v.Uint(), nil
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), nil
+	case reflect.Map:
+		return n.normalizeMap(v)
+	case reflect.Slice:
+		if v.IsNil() {
+			return nil, nil
+		}
+		if v.Type().Elem().Kind() == reflect.Uint8 && !reflect.PointerTo(v.Type().Elem()).Implements(jsonMarshalerType) {
+			// Byte slices are encoded as base64 strings, like encoding/json does.
+			return base64.StdEncoding.EncodeToString(v.Bytes()), nil
+		}
+		if err := n.enter(v); err != nil {
+			return nil, err
+		}
+		defer n.leave(v)
+		return n.normalizeArray(v)
+	case reflect.Array:
+		return n.normalizeArray(v)
+	case reflect.Struct:
+		return n.normalizeStruct(v)
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedInstanceType, v.Type())
+	}
+}
+
+// marshalValue converts a value implementing json.Marshaler or encoding.TextMarshaler through its
+// own encoding. It reports whether the value implements one of them.
+func marshalValue(v reflect.Value) (interface{}, bool, error) {
+	if v.Kind() == reflect.Pointer && v.IsNil() || !v.CanInterface() {
+		return nil, false, nil
+	}
+	if !v.Type().Implements(jsonMarshalerType) && !v.Type().Implements(textMarshalerType) && v.CanAddr() {
+		// Methods with a pointer receiver apply to addressable values, like struct fields.
+		v = v.Addr()
+	}
+
+	switch marshaler := v.Interface().(type) {
+	case json.Marshaler:
+		data, err := marshaler.MarshalJSON()
+		if err != nil {
+			return nil, true, fmt.Errorf("%w: %w", ErrInstanceMarshalError, err)
+		}
+		// Numbers are decoded as json.Number, as by ValidateJSON, so that they keep their exact value.
+		converted, err := decodeJSON(bytes.NewReader(data))
+		if err != nil {
+			return nil, true, fmt.Errorf("%w: %w", ErrInstanceMarshalError, err)
+		}
+		return converted, true, nil
+	case encoding.TextMarshaler:
+		text, err := marshaler.MarshalText()
+		if err != nil {
+			return nil, true, fmt.Errorf("%w: %w", ErrInstanceMarshalError, err)
+		}
+		return string(text), true, nil
+	}
+	return nil, false, nil
+}
+
+// normalizeMap converts a map into a JSON object. Keys are strings, integers or values
+// implementing encoding.TextMarshaler.
+func (n *normalizer) normalizeMap(v reflect.Value) (interface{}, error) {
+	if v.IsNil() {
+		return nil, nil
+	}
+	if err := n.enter(v); err != nil {
+		return nil, err
+	}
+	defer n.leave(v)
+
+	object := make(map[string]interface{}, v.Len())
+	iter := v.MapRange()
+	for iter.Next() {
+		key, err := mapKey(iter.Key())
+		if err != nil {
+			return nil, err
+		}
+		value, err := n.normalizeValue(iter.Value())
+		if err != nil {
+			return nil, within(err, key)
+		}
+		object[key] = value
+	}
+	return object, nil
+}
+
+// mapKey returns the name of the JSON object member for a map key.
+func mapKey(key reflect.Value) (string, error) {
+	if key.Kind() == reflect.String {
+		return key.String(), nil
+	}
+	if !key.CanInterface() {
+		return "", fmt.Errorf("%w: map key %s", ErrUnsupportedInstanceType, key.Type())
+	}
+	if marshaler, ok := key.Interface().(encoding.TextMarshaler); ok {
+		text, err := marshaler.MarshalText()
+		if err != nil {
+			return "", fmt.Errorf("%w: %w", ErrInstanceMarshalError, err)
+		}
+		return string(text), nil
+	}
+	switch key.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(key.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return strconv.FormatUint(key.Uint(), 10), nil
+	}
+	return "", fmt.Errorf("%w: map key %s", ErrUnsupportedInstanceType, key.Type())
+}
+
+// normalizeArray converts a slice or an array into a JSON array.
+func (n *normalizer) normalizeArray(v reflect.Value) (interface{}, error) {
+	array := make([]interface{}, v.Len())
+	for i := range array {
+		item, err := n.normalizeValue(v.Index(i))
+		if err != nil {
+			return nil, within(err, strconv.Itoa(i))
+		}
+		array[i] = item
+	}
+	return array, nil
+}
+
+// normalizeStruct converts a struct into a JSON object, according to the json tags of its fields.
+func (n *normalizer) normalizeStruct(v reflect.Value) (interface{}, error) {
+	object := make(map[string]interface{})
+	for _, field := range cachedStructFields(v.Type()) {
+		fieldValue, ok := fieldByIndex(v, field.index)
+		if !ok || field.omitEmpty && isEmptyValue(fieldValue) {
+			continue
+		}
+
+		value, err := n.normalizeValue(fieldValue)
+		if err != nil {
+			return nil, within(err, field.name)
+		}
+		if field.quoted {
+			value = quoteValue(value)
+		}
+		object[field.name] = value
+	}
+	return object, nil
+}
+
+// fieldByIndex returns the nested field of a struct, and false when it is reached through a
+// nil embedded pointer.
+func fieldByIndex(v reflect.Value, index []int) (reflect.Value, bool) {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Pointer {
+			if v.IsNil() {
+				return reflect.Value{}, false
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v, true
+}
+
+// quoteValue encodes a boolean, number or string as a JSON string, for fields with the ",string"
+// option of their json tag.
+func quoteValue(value interface{}) interface{} {
+	switch value.(type) {
+	case string, bool, int64, uint64, float64:
+		data, err := json.Marshal(value)
+		if err == nil {
+			return string(data)
+		}
+	}
+	return value
+}
+
+// isEmptyValue tells whether a field tagged omitempty is omitted, following encoding/json.
+func isEmptyValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Bool:
+		return !v.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int() == 0
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return v.Uint() == 0
+	case reflect.Float32, reflect.Float64:
+		return v.Float() == 0
+	case reflect.Interface, reflect.Pointer:
+		return v.IsNil()
+	}
+	return false
+}
+
+// structField is a struct field encoded as a JSON object member.
+type structField struct {
+	name      string
+	index     []int
+	tagged    bool
+	omitEmpty bool
+	quoted    bool
+}
+
+// structFieldsCache holds the encoded fields of the struct types seen so far.
+var structFieldsCache sync.Map // map[reflect.Type][]structField
+
+// cachedStructFields returns the fields of a struct type encoded as JSON object members.
+func cachedStructFields(t reflect.Type) []structField {
+	if fields, ok := structFieldsCache.Load(t); ok {
+		return fields.([]structField)
+	}
+	fields, _ := structFieldsCache.LoadOrStore(t, structFields(t))
+	return fields.([]structField)
+}
+
+// structFields lists the fields of a struct type encoded as JSON object members, including
+// the fields promoted from embedded structs. Among fields of the same name, the least nested
+// one wins, then the tagged one; fields that remain ambiguous are dropped, as in encoding/json.
+func structFields(t reflect.Type) []structField {
+	type candidate struct {
+		structField
+		depth int
+	}
+
+	var candidates []candidate
+	visited := map[reflect.Type]bool{}
+	var walk func(t reflect.Type, index []int)
+	walk = func(t reflect.Type, index []int) {
+		if visited[t] {
+			return
+		}
+		visited[t] = true
+		defer delete(visited, t)
+
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			tag := field.Tag.Get("json")
+			if tag == "-" {
+				continue
+			}
+
+			fieldType := field.Type
+			if fieldType.Kind() == reflect.Pointer {
+				fieldType = fieldType.Elem()
+			}
+			if field.Anonymous {
+				if !field.IsExported() && fieldType.Kind() != reflect.Struct {
+					continue
+				}
+			} else if !field.IsExported() {
+				continue
+			}
+
+			name, options, _ := strings.Cut(tag, ",")
+			fieldIndex := append(append([]int(nil), index...), i)
+			if name == "" && field.Anonymous && fieldType.Kind() == reflect.Struct {
+				walk(fieldType, fieldIndex)
+				continue
+			}
+
+			candidates = append(candidates, candidate{
+				structField: structField{
+					name:      nameOrDefault(name, field.Name),
+					index:     fieldIndex,
+					tagged:    name != "",
+					omitEmpty: hasTagOption(options, "omitempty"),
+					quoted:    hasTagOption(options, "string") && isQuotableKind(fieldType.Kind()),
+				},
+				depth: len(fieldIndex),
+			})
+		}
+	}
+	walk(t, nil)
+
+	byName := map[string][]candidate{}
+	var names []string
+	for _, c := range candidates {
+		if _, ok := byName[c.name]; !ok {
+			names = append(names, c.name)
+		}
+		byName[c.name] = append(byName[c.name], c)
+	}
+
+	var fields []structField
+	for _, name := range names {
+		group := byName[name]
+		sort.SliceStable(group, func(i, j int) bool {
+			if group[i].depth != group[j].depth {
+				return group[i].depth < group[j].depth
+			}
+			return group[i].tagged && !group[j].tagged
+		})
+		if len(group) > 1 && group[0].depth == group[1].depth && group[0].tagged == group[1].tagged {
+			continue // Ambiguous fields are omitted.
+		}
+		fields = append(fields, group[0].structField)
+	}
+	return fields
+}
+
+// nameOrDefault returns name, or fallback when name is empty.
+func nameOrDefault(name, fallback string) string {
+	if name != "" {
+		return name
+	}
+	return fallback
+}
+
+// hasTagOption tells whether the comma-separated options of a json tag include option.
+func hasTagOption(options, option string) bool {
+	for options != "" {
+		var current string
+		current, options, _ = strings.Cut(options, ",")
+		if current == option {
+			return true
+		}
+	}
+	return false
+}
+
+// isQuotableKind tells whether the ",string" tag option applies to a field of the given kind.
+func isQuotableKind(kind reflect.Kind) bool {
+	switch kind {
+	case reflect.Bool, reflect.String,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
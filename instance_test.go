@@ -0,0 +1,194 @@
+package jsonschema
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/goccy/go-json"
+	"github.com/test-go/testify/assert"
+)
+
+type testAddress struct {
+	Street string `json:"street"`
+	City   string `json:"city,omitempty"`
+}
+
+type testAudit struct {
+	CreatedAt time.Time `json:"createdAt"`
+	CreatedBy string    `json:"createdBy,omitempty"`
+}
+
+type testLevel int
+
+type testUser struct {
+	testAudit
+	Name     string            `json:"name"`
+	Age      int               `json:"age,omitempty"`
+	Email    *string           `json:"email,omitempty"`
+	Password string            `json:"-"`
+	Level    testLevel         `json:"level"`
+	ID       int64             `json:"id,string"`
+	Address  *testAddress      `json:"address,omitempty"`
+	Tags     []string          `json:"tags"`
+	Labels   map[string]string `json:"labels,omitempty"`
+	Extra    json.RawMessage   `json:"extra,omitempty"`
+	Nickname string
+	internal string
+}
+
+type testNode struct {
+	Name string    `json:"name"`
+	Next *testNode `json:"next,omitempty"`
+}
+
+type failingMarshaler struct{}
+
+func (failingMarshaler) MarshalJSON() ([]byte, error) {
+	return nil, errors.New("cannot marshal")
+}
+
+func TestNormalizeInstance(t *testing.T) {
+	email := "jane@example.com"
+	createdAt := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	t.Run("struct", func(t *testing.T) {
+		normalized, err := normalizeInstance(&testUser{
+			testAudit: testAudit{CreatedAt: createdAt},
+			Name:      "Jane",
+			Email:     &email,
+			Password:  "secret",
+			Level:     3,
+			ID:        42,
+			Address:   &testAddress{Street: "Main Street"},
+			Extra:     json.RawMessage(`{"a": [1, true]}`),
+			Nickname:  "JJ",
+			internal:  "hidden",
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]interface{}{
+			"createdAt": "2024-05-01T12:00:00Z",
+			"name":      "Jane",
+			"email":     "jane@example.com",
+			"level":     int64(3),
+			"id":        "42",
+			"address":   map[string]interface{}{"street": "Main Street"},
+			"tags":      nil,
+			"extra":     map[string]interface{}{"a": []interface{}{json.Number("1"), true}},
+			"Nickname":  "JJ",
+		}, normalized)
+	})
+
+	t.Run("typed collections", func(t *testing.T) {
+		normalized, err := normalizeInstance(map[int][]testAddress{1: {{Street: "a", City: "b"}}})
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]interface{}{
+			"1": []interface{}{map[string]interface{}{"street": "a", "city": "b"}},
+		}, normalized)
+
+		normalized, err = normalizeInstance([]interface{}{[]byte("hi"), [2]bool{true, false}, json.Number("1.5")})
+		assert.NoError(t, err)
+		assert.Equal(t, []interface{}{"aGk=", []interface{}{true, false}, json.Number("1.5")}, normalized)
+	})
+
+	t.Run("exact numbers", func(t *testing.T) {
+		normalized, err := normalizeInstance(json.RawMessage(`{"id": 9007199254740993, "rate": 0.1000000000000000055511151231257827}`))
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]interface{}{
+			"id":   json.Number("9007199254740993"),
+			"rate": json.Number("0.1000000000000000055511151231257827"),
+		}, normalized)
+
+		schema, err := NewCompiler().Compile([]byte(`{"properties": {"id": {"maximum": 18446744073709551615}}}`))
+		assert.NoError(t, err)
+		assert.True(t, schema.Validate(json.RawMessage(`{"id": 18446744073709551615}`)).IsValid())
+		assert.False(t, schema.Validate(json.RawMessage(`{"id": 18446744073709551616}`)).IsValid(), "Expected an integer beyond float64 precision to be compared exactly")
+	})
+
+	t.Run("generic values are not copied", func(t *testing.T) {
+		items := []interface{}{float64(1), "a"}
+		instance := map[string]interface{}{"items": items}
+
+		normalized, err := normalizeInstance(instance)
+		assert.NoError(t, err)
+		normalizedItems := normalized.(map[string]interface{})["items"].([]interface{})
+		assert.True(t, &normalizedItems[0] == &items[0])
+		assert.Zero(t, testing.AllocsPerRun(10, func() {
+			_, _ = normalizeInstance(instance)
+		}), "Expected generic values to be returned without allocating")
+
+		wrapped, err := normalizeInstance(struct {
+			Items []interface{} `json:"items"`
+		}{items})
+		assert.NoError(t, err)
+		wrappedItems := wrapped.(map[string]interface{})["items"].([]interface{})
+		assert.True(t, &wrappedItems[0] == &items[0], "Expected generic values held by a struct not to be copied")
+
+		instance["at"] = createdAt
+		normalized, err = normalizeInstance(instance)
+		assert.NoError(t, err)
+		assert.Equal(t, "2024-05-01T12:00:00Z", normalized.(map[string]interface{})["at"])
+		assert.Equal(t, createdAt, instance["at"], "Expected the instance to be left untouched")
+	})
+
+	t.Run("unsupported values", func(t *testing.T) {
+		_, err := normalizeInstance(map[string]interface{}{"c": make(chan int)})
+		assert.True(t, errors.Is(err, ErrUnsupportedInstanceType), "Expected ErrUnsupportedInstanceType, got %v", err)
+		assert.EqualError(t, err, "unsupported instance type: chan int at /c")
+
+		_, err = normalizeInstance(&testUser{Extra: json.RawMessage(`{`)})
+		assert.True(t, errors.Is(err, ErrInstanceMarshalError), "Expected ErrInstanceMarshalError, got %v", err)
+		assert.Contains(t, err.Error(), " at /extra")
+
+		_, err = normalizeInstance([]failingMarshaler{{}})
+		assert.True(t, errors.Is(err, ErrInstanceMarshalError), "Expected ErrInstanceMarshalError, got %v", err)
+	})
+
+	t.Run("cycles", func(t *testing.T) {
+		node := &testNode{Name: "a"}
+		node.Next = node
+		_, err := normalizeInstance(node)
+		assert.True(t, errors.Is(err, ErrUnsupportedInstanceType), "Expected ErrUnsupportedInstanceType, got %v", err)
+		assert.Contains(t, err.Error(), "encountered a cycle via *jsonschema.testNode at /next/next/")
+
+		object := map[string]interface{}{}
+		object["self"] = []interface{}{object}
+		_, err = normalizeInstance(object)
+		assert.True(t, errors.Is(err, ErrUnsupportedInstanceType), "Expected ErrUnsupportedInstanceType, got %v", err)
+
+		// Deep values are not mistaken for cycles.
+		deep := &testNode{Name: "0"}
+		for i := 1; i <= 2*startDetectingCyclesAfter; i++ {
+			deep = &testNode{Name: strconv.Itoa(i), Next: deep}
+		}
+		_, err = normalizeInstance(deep)
+		assert.NoError(t, err)
+	})
+}
+
+func TestValidateStruct(t *testing.T) {
+	schema, err := NewCompiler().Compile([]byte(`{
+		"type": "object",
+		"properties": {
+			"name": {"type": "string", "minLength": 1},
+			"age": {"type": "integer", "minimum": 18},
+			"level": {"type": "integer"},
+			"createdAt": {"type": "string", "format": "date-time"},
+			"address": {"type": "object", "required": ["street"]}
+		},
+		"required": ["name", "createdAt"]
+	}`))
+	assert.NoError(t, err)
+
+	user := testUser{Name: "Jane", Age: 30, testAudit: testAudit{CreatedAt: time.Now()}}
+	assert.True(t, schema.Validate(user).IsValid())
+	assert.True(t, schema.Validate(&user).IsValid())
+
+	user.Age = 12
+	assert.False(t, schema.Validate(user).IsValid())
+
+	result := schema.Validate(make(chan int))
+	assert.False(t, result.IsValid())
+	assert.Contains(t, result.Errors, "instance")
+}
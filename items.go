@@ -1,7 +1,6 @@
 package jsonschema
 
 import (
-	"fmt"
 	"strconv"
 	"strings"
 )
@@ -17,7 +16,7 @@ import (
 // If any array element does not conform, it returns a EvaluationError detailing the issue.
 //
 // Reference: https://json-schema.org/draft/2020-12/json-schema-core#name-items
-func evaluateItems(schema *Schema, array []interface{}, evaluatedProps map[string]bool, evaluatedItems map[int]bool, dynamicScope *DynamicScope) ([]*EvaluationResult, *EvaluationError) {
+func evaluateItems(schema *Schema, array []interface{}, result *EvaluationResult, evaluatedProps map[string]bool, evaluatedItems map[int]bool, dynamicScope *DynamicScope) ([]*EvaluationResult, *EvaluationError) {
 	if schema.Items == nil {
 		return nil, nil // // No 'items' constraints to validate against
 	}
@@ -33,27 +32,34 @@ func evaluateItems(schema *Schema, array []interface{}, evaluatedProps map[strin
 		// Ensure that we only access indices within the range of existing array elements
 		for i := startIndex; i < len(array); i++ {
 			item := array[i]
-			result, _, _ := schema.Items.evaluate(item, dynamicScope)
-			if result != nil {
-				anchor := fmt.Sprintf("/items/%d", i)
-				instanceLocation := fmt.Sprintf("/%d", i)
+			itemResult, _, _ := schema.Items.evaluateAt(item, "/items", strconv.Itoa(i), dynamicScope)
+			if itemResult != nil {
+				results = append(results, itemResult)
 
-				result.SetEvaluationPath(anchor).
-					SetSchemaLocation(schema.GetSchemaLocation(anchor)).
-					SetInstanceLocation(instanceLocation)
-
-				result.Details = recursiveUpdateDetailsWithItemAnchor(schema, result.Details, anchor, instanceLocation)
-
-				if result.IsValid() {
-					evaluatedItems[i] = true // Mark the item as evaluated if it passes schema validation.
+				if itemResult.IsValid() {
+					if evaluatedItems != nil {
+						evaluatedItems[i] = true // Mark the item as evaluated if it passes schema validation.
+					}
 				} else {
 					invalid_indexs = append(invalid_indexs, strconv.Itoa(i))
-					results = append(results, result)
 				}
 			}
+
+			if dynamicScope.isCanceled() {
+				// The items left were not checked, so the keyword cannot be reported as valid.
+				return results, newEvaluationCanceledError(dynamicScope.Context().Err())
+			}
+			if dynamicScope.stops(len(invalid_indexs) > 0) {
+				break // Stop at the item where the validation failed fast.
+			}
 		}
 	}
 
+	// The annotation is true as the subschema applied to every item after the prefix items.
+	if len(array) > startIndex && dynamicScope.annotates() {
+		result.AddAnnotation("items", true)
+	}
+
 	if len(invalid_indexs) == 1 {
 		return results, NewEvaluationError("items", "item_mismatch", "Item at index {index} does not match the schema", map[string]interface{}{
 			"index": invalid_indexs[0],
@@ -65,15 +71,3 @@ func evaluateItems(schema *Schema, array []interface{}, evaluatedProps map[strin
 	}
 	return results, nil
 }
-
-func recursiveUpdateDetailsWithItemAnchor(schema *Schema, details []*EvaluationResult, anchor string, instanceLocation string) []*EvaluationResult {
-	for key, value := range details {
-		originEvalPath := value.EvaluationPath
-		value.SetEvaluationPath(fmt.Sprintf("%s%s", anchor, originEvalPath)).
-			SetSchemaLocation(schema.GetSchemaLocation(fmt.Sprintf("%s%s", anchor, originEvalPath))).
-			SetInstanceLocation(fmt.Sprintf("%s%s", instanceLocation, value.InstanceLocation))
-		value.Details = recursiveUpdateDetailsWithItemAnchor(schema, value.Details, anchor, instanceLocation)
-		details[key] = value
-	}
-	return details
-}
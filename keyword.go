@@ -0,0 +1,248 @@
+package jsonschema
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/goccy/go-json"
+)
+
+// Keyword is a custom schema keyword. Once registered on a Compiler with RegisterKeyword, every
+// schema compiled by that compiler which uses the keyword gets it compiled and evaluated along
+// with the standard keywords.
+type Keyword interface {
+	// Compile parses the raw JSON value of the keyword in schema. It is called once for each
+	// schema using the keyword, when the schema is compiled, and returns an error to reject an
+	// invalid value. Subschemas of the value can be decoded with json.Unmarshal into a Schema.
+	Compile(schema *Schema, value json.RawMessage) (KeywordEvaluator, error)
+}
+
+// KeywordEvaluator evaluates a compiled keyword against the instances validated by its schema.
+type KeywordEvaluator interface {
+	// Evaluate checks the instance of e and reports its outcome through the methods of e.
+	Evaluate(e *KeywordEvaluation)
+}
+
+// KeywordSubschemas is implemented by keyword evaluators whose value contains subschemas. The
+// subschemas are keyed by their JSON Pointer relative to the keyword value, such as "" for the
+// value itself or "/0" for its first item. They are compiled with the enclosing schema, so their
+// references are resolved, and they can be the target of a JSON Pointer reference.
+type KeywordSubschemas interface {
+	Subschemas() map[string]*Schema
+}
+
+// RegisterKeyword registers a custom keyword under the given name. Schemas compiled afterwards
+// use it wherever the name appears as a keyword. The names of standard keywords cannot be taken
+// over: RegisterKeyword panics with ErrStandardKeyword for them, as the keyword would never run.
+func (c *Compiler) RegisterKeyword(name string, keyword Keyword) *Compiler {
+	if schemaKeywords[name] {
+		panic(fmt.Errorf("%w: %s", ErrStandardKeyword, name))
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.keywords[name] = keyword
+	return c
+}
+
+// getKeyword returns the custom keyword registered under the given name.
+func (c *Compiler) getKeyword(name string) (Keyword, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	keyword, ok := c.keywords[name]
+	return keyword, ok
+}
+
+// compiledKeyword is a custom keyword compiled for a schema.
+type compiledKeyword struct {
+	name       string
+	evaluator  KeywordEvaluator
+	subschemas map[string]*Schema
+}
+
+// deprecatedKeywords holds the names of the custom keywords that are also decoded into a deprecated
+// field of Schema, so that they are compiled as custom keywords too.
+var deprecatedKeywords = map[string]bool{
+	"x-tf-accepted-objects": true,
+	"x-tf-facets":           true,
+}
+
+// schemaKeywords holds the names of the standard keywords decoded into the fields of Schema.
+var schemaKeywords = func() map[string]bool {
+	schemaType := reflect.TypeOf(Schema{})
+	names := make(map[string]bool, schemaType.NumField())
+	for i := 0; i < schemaType.NumField(); i++ {
+		name, _, _ := strings.Cut(schemaType.Field(i).Tag.Get("json"), ",")
+		if name != "" && name != "-" && !deprecatedKeywords[name] {
+			names[name] = true
+		}
+	}
+	return names
+}()
+
+// compileKeywords compiles the custom keywords of the compiler that appear in s, in name order.
+// An invalid value is reported together with the location of the keyword in the schema document.
+func (s *Schema) compileKeywords(compiler *Compiler) error {
+	names := make([]string, 0, len(s.unknownKeywords))
+	for name := range s.unknownKeywords {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	s.keywords = nil
+	for _, name := range names {
+		keyword, ok := compiler.getKeyword(name)
+		if !ok {
+			continue
+		}
+
+		evaluator, err := keyword.Compile(s, s.unknownKeywords[name])
+		if err != nil {
+			location := s.location + "/" + escapeJSONPointerSegment(name)
+			return fmt.Errorf("%w %s at %s: %w", ErrInvalidKeywordValue, name, s.getRootSchema().GetSchemaLocation(location), err)
+		}
+
+		compiled := &compiledKeyword{name: name, evaluator: evaluator}
+		if withSubschemas, ok := evaluator.(KeywordSubschemas); ok {
+			compiled.subschemas = withSubschemas.Subschemas()
+		}
+		s.keywords = append(s.keywords, compiled)
+	}
+	return nil
+}
+
+// keywordSubschemas lists the subschemas of the custom keywords of s, in keyword and pointer order.
+func (s *Schema) keywordSubschemas() []subschema {
+	var subs []subschema
+	for _, keyword := range s.keywords {
+		pointers := make([]string, 0, len(keyword.subschemas))
+		for pointer := range keyword.subschemas {
+			pointers = append(pointers, pointer)
+		}
+		sort.Strings(pointers)
+
+		for _, pointer := range pointers {
+			if schema := keyword.subschemas[pointer]; schema != nil {
+				subs = append(subs, subschema{schema: schema, segments: append([]string{keyword.name}, splitJSONPointer(pointer)...)})
+			}
+		}
+	}
+	return subs
+}
+
+// splitJSONPointer returns the unescaped reference tokens of a JSON Pointer.
+func splitJSONPointer(pointer string) []string {
+	if pointer == "" {
+		return nil
+	}
+
+	segments := strings.Split(strings.TrimPrefix(pointer, "/"), "/")
+	for i, segment := range segments {
+		segments[i] = strings.ReplaceAll(strings.ReplaceAll(segment, "~1", "/"), "~0", "~")
+	}
+	return segments
+}
+
+// KeywordEvaluation is the evaluation of a custom keyword against an instance. Its methods
+// report errors and annotations of the keyword, mark the properties and items it evaluated for
+// unevaluatedProperties and unevaluatedItems, and apply the subschemas of the keyword.
+type KeywordEvaluation struct {
+	Keyword          string        // Name of the keyword.
+	Schema           *Schema       // Schema containing the keyword.
+	Instance         interface{}   // Instance being evaluated.
+	InstanceLocation string        // JSON Pointer of the instance within the validated document.
+	DynamicScope     *DynamicScope // Dynamic scope of the evaluation.
+
+	subschemas     map[string]*Schema
+	result         *EvaluationResult
+	evaluatedProps map[string]bool
+	evaluatedItems map[int]bool
+}
+
+// AddError reports that the instance does not satisfy the keyword.
+func (e *KeywordEvaluation) AddError(err *EvaluationError) {
+	e.result.AddError(err)
+}
+
+// AddAnnotation records the annotation produced by the keyword.
+func (e *KeywordEvaluation) AddAnnotation(value interface{}) {
+	e.result.AddAnnotation(e.Keyword, value)
+}
+
+// MarkPropertyEvaluated marks a property of the instance as evaluated by the keyword.
+func (e *KeywordEvaluation) MarkPropertyEvaluated(name string) {
+	if e.evaluatedProps != nil {
+		e.evaluatedProps[name] = true
+	}
+}
+
+// MarkItemEvaluated marks an item of the instance as evaluated by the keyword.
+func (e *KeywordEvaluation) MarkItemEvaluated(index int) {
+	if e.evaluatedItems != nil {
+		e.evaluatedItems[index] = true
+	}
+}
+
+// Evaluate applies the subschema of the keyword found at the given pointer to the instance
+// itself. When the instance is valid, the properties and items evaluated by the subschema count
+// as evaluated by the keyword. The result is added to the details of the schema's result, and is
+// nil when the keyword has no subschema at the pointer.
+func (e *KeywordEvaluation) Evaluate(pointer string) *EvaluationResult {
+	subschema := e.subschemas[pointer]
+	if subschema == nil {
+		return nil
+	}
+
+	result, props, items := subschema.evaluateIn(e.Instance, e.keywordPath(pointer), e.DynamicScope)
+	result.locate()
+	e.result.AddDetail(result)
+	if result.IsValid() {
+		mergeStringMaps(e.evaluatedProps, props)
+		mergeIntMaps(e.evaluatedItems, items)
+	}
+	return result
+}
+
+// EvaluateChild applies the subschema of the keyword found at the given pointer to the member or
+// item of the instance named by segment, whose value is child. The result is added to the details
+// of the schema's result, and is nil when the keyword has no subschema at the pointer.
+func (e *KeywordEvaluation) EvaluateChild(pointer string, segment string, child interface{}) *EvaluationResult {
+	subschema := e.subschemas[pointer]
+	if subschema == nil {
+		return nil
+	}
+
+	result, _, _ := subschema.evaluateAt(child, e.keywordPath(pointer), segment, e.DynamicScope)
+	result.locate()
+	e.result.AddDetail(result)
+	return result
+}
+
+// keywordPath returns the escaped keyword path of the subschema of the keyword found at the given pointer.
+func (e *KeywordEvaluation) keywordPath(pointer string) string {
+	return "/" + escapeJSONPointerSegment(e.Keyword) + pointer
+}
+
+// evaluateKeywords evaluates the custom keywords of schema against the instance.
+func evaluateKeywords(schema *Schema, instance interface{}, result *EvaluationResult, evaluatedProps map[string]bool, evaluatedItems map[int]bool, dynamicScope *DynamicScope) {
+	for _, keyword := range schema.keywords {
+		if dynamicScope.stops(!result.Valid) {
+			break
+		}
+		keyword.evaluator.Evaluate(&KeywordEvaluation{
+			Keyword:          keyword.name,
+			Schema:           schema,
+			Instance:         instance,
+			InstanceLocation: dynamicScope.InstanceLocation(),
+			DynamicScope:     dynamicScope,
+			subschemas:       keyword.subschemas,
+			result:           result,
+			evaluatedProps:   evaluatedProps,
+			evaluatedItems:   evaluatedItems,
+		})
+	}
+}
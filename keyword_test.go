@@ -0,0 +1,265 @@
+package jsonschema
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/goccy/go-json"
+	"github.com/test-go/testify/assert"
+)
+
+// evenKeyword requires numbers to be even when its value is true, and records where it was evaluated.
+type evenKeyword struct {
+	locations []string
+}
+
+func (k *evenKeyword) Compile(schema *Schema, value json.RawMessage) (KeywordEvaluator, error) {
+	var enabled bool
+	if err := json.Unmarshal(value, &enabled); err != nil {
+		return nil, fmt.Errorf("expected a boolean: %w", err)
+	}
+	return &evenEvaluator{keyword: k, enabled: enabled}, nil
+}
+
+type evenEvaluator struct {
+	keyword *evenKeyword
+	enabled bool
+}
+
+func (e *evenEvaluator) Evaluate(evaluation *KeywordEvaluation) {
+	e.keyword.locations = append(e.keyword.locations, evaluation.InstanceLocation)
+
+	number, ok := evaluation.Instance.(float64)
+	if !ok || !e.enabled {
+		return
+	}
+	if int64(number)%2 != 0 {
+		evaluation.AddError(NewEvaluationError(evaluation.Keyword, "not_even", "Value {value} is not even", map[string]interface{}{
+			"value": number,
+		}))
+		return
+	}
+	evaluation.AddAnnotation(true)
+}
+
+// knownKeyword marks the listed properties as evaluated.
+type knownKeyword struct{}
+
+func (knownKeyword) Compile(schema *Schema, value json.RawMessage) (KeywordEvaluator, error) {
+	var names []string
+	if err := json.Unmarshal(value, &names); err != nil {
+		return nil, err
+	}
+	return knownEvaluator(names), nil
+}
+
+type knownEvaluator []string
+
+func (names knownEvaluator) Evaluate(evaluation *KeywordEvaluation) {
+	for _, name := range names {
+		evaluation.MarkPropertyEvaluated(name)
+	}
+}
+
+// valuesKeyword applies a subschema to every property value of an object.
+type valuesKeyword struct{}
+
+func (valuesKeyword) Compile(schema *Schema, value json.RawMessage) (KeywordEvaluator, error) {
+	subschema := &Schema{}
+	if err := json.Unmarshal(value, subschema); err != nil {
+		return nil, err
+	}
+	return &valuesEvaluator{schema: subschema}, nil
+}
+
+type valuesEvaluator struct {
+	schema *Schema
+}
+
+func (e *valuesEvaluator) Subschemas() map[string]*Schema {
+	return map[string]*Schema{"": e.schema}
+}
+
+func (e *valuesEvaluator) Evaluate(evaluation *KeywordEvaluation) {
+	object, ok := evaluation.Instance.(map[string]interface{})
+	if !ok {
+		return
+	}
+	for name, value := range object {
+		if !evaluation.EvaluateChild("", name, value).IsValid() {
+			evaluation.AddError(NewEvaluationError(evaluation.Keyword, "value_mismatch", "Value of {property} does not match the schema", map[string]interface{}{
+				"property": name,
+			}))
+		}
+		evaluation.MarkPropertyEvaluated(name)
+	}
+}
+
+func TestCustomKeywords(t *testing.T) {
+	even := &evenKeyword{}
+	compiler := NewCompiler().
+		RegisterKeyword("x-even", even).
+		RegisterKeyword("x-known", knownKeyword{}).
+		RegisterKeyword("x-values", valuesKeyword{})
+
+	t.Run("errors and annotations", func(t *testing.T) {
+		schema, err := compiler.Compile([]byte(`{"x-even": true}`))
+		assert.NoError(t, err)
+
+		result := schema.Validate(float64(4))
+		assert.True(t, result.IsValid())
+		assert.Equal(t, true, result.Annotations["x-even"])
+
+		result = schema.Validate(float64(3))
+		assert.False(t, result.IsValid())
+		if assert.Contains(t, result.Errors, "x-even") {
+			assert.Equal(t, "Value 3 is not even", result.Errors["x-even"].Error())
+		}
+	})
+
+	t.Run("invalid value", func(t *testing.T) {
+		_, err := compiler.Compile([]byte(`{"properties": {"n": {"x-even": "yes"}}}`))
+		assert.True(t, errors.Is(err, ErrInvalidKeywordValue), "Expected ErrInvalidKeywordValue, got %v", err)
+		assert.Contains(t, err.Error(), "#/properties/n/x-even")
+	})
+
+	t.Run("evaluated properties", func(t *testing.T) {
+		schema, err := compiler.Compile([]byte(`{"x-known": ["a"], "unevaluatedProperties": false}`))
+		assert.NoError(t, err)
+		assert.True(t, schema.Validate(map[string]interface{}{"a": 1}).IsValid())
+		assert.False(t, schema.Validate(map[string]interface{}{"a": 1, "b": 2}).IsValid())
+	})
+
+	t.Run("subschemas", func(t *testing.T) {
+		schema, err := compiler.Compile([]byte(`{
+			"$defs": {
+				"positive": {"exclusiveMinimum": 0},
+				"counts": {"x-values": {"$ref": "#/$defs/positive"}}
+			},
+			"properties": {
+				"counts": {"$ref": "#/$defs/counts"},
+				"total": {"$ref": "#/$defs/counts/x-values"}
+			}
+		}`))
+		assert.NoError(t, err)
+		assert.Empty(t, schema.CompileWarnings())
+
+		assert.True(t, schema.Validate(map[string]interface{}{"counts": map[string]interface{}{"a": 1.0}, "total": 1.0}).IsValid())
+		assert.False(t, schema.Validate(map[string]interface{}{"counts": map[string]interface{}{"a": -1.0}}).IsValid())
+		assert.False(t, schema.Validate(map[string]interface{}{"total": -1.0}).IsValid())
+	})
+
+	t.Run("instance location", func(t *testing.T) {
+		schema, err := compiler.Compile([]byte(`{"items": {"properties": {"a/b": {"x-even": false}}}}`))
+		assert.NoError(t, err)
+
+		even.locations = nil
+		schema.Validate([]interface{}{map[string]interface{}{}, map[string]interface{}{"a/b": 1.0}})
+		assert.Equal(t, []string{"/1/a~1b"}, even.locations)
+	})
+
+	t.Run("unregistered keyword", func(t *testing.T) {
+		schema, err := NewCompiler().Compile([]byte(`{"x-even": true}`))
+		assert.NoError(t, err)
+		assert.True(t, schema.Validate(float64(3)).IsValid())
+	})
+}
+
+func TestRegisterKeywordStandardName(t *testing.T) {
+	defer func() {
+		err, _ := recover().(error)
+		assert.True(t, errors.Is(err, ErrStandardKeyword), "Expected a panic with ErrStandardKeyword, got %v", err)
+	}()
+
+	NewCompiler().RegisterKeyword("minimum", knownKeyword{})
+}
+
+func TestTFKeywords(t *testing.T) {
+	objects := map[string]string{
+		"tf://objects/employment": `{"@archetype": "form", "@kind": "EMPLOYMENT_DETAILS"}`,
+		"tf://objects/personal":   `{"@archetype": "form", "@kind": "PERSONAL_DETAILS", "@schema": "tf://types/person/v2"}`,
+	}
+	compiler := NewCompiler().
+		RegisterLoader("tf", func(url string) (io.ReadCloser, error) {
+			object, ok := objects[url]
+			if !ok {
+				return nil, ErrFailedToFetch
+			}
+			return io.NopCloser(strings.NewReader(object)), nil
+		})
+
+	schemaJSON := []byte(`{
+		"x-tf-facets": ["BASIC_INFO"],
+		"properties": {
+			"employment": {
+				"type": "string",
+				"x-tf-accepted-objects": [{"@archetype": "form", "@kind": "EMPLOYMENT_DETAILS"}]
+			},
+			"person": {
+				"type": "string",
+				"x-tf-accepted-objects": ["tf://types/person"]
+			}
+		}
+	}`)
+	schema, err := compiler.Compile(schemaJSON)
+	assert.NoError(t, err)
+
+	result := schema.Validate(map[string]interface{}{
+		"employment": "tf://objects/employment",
+		"person":     "tf://objects/personal",
+	})
+	assert.True(t, result.IsValid())
+	assert.Equal(t, []Annotation{{Keyword: "x-tf-facets", Value: []string{"BASIC_INFO"}}}, filterAnnotations(result.AnnotationsAt(""), "x-tf-facets"))
+
+	result = schema.Validate(map[string]interface{}{
+		"employment": "tf://objects/personal",
+		"person":     "tf://objects/missing",
+	})
+	assert.False(t, result.IsValid())
+	var codes []string
+	for _, violation := range result.Violations() {
+		codes = append(codes, violation.InstancePath+" "+violation.Code)
+	}
+	sort.Strings(codes)
+	assert.Equal(t, []string{"/employment id_forbidden_schema", "/person id_cant_reach"}, codes)
+
+	// The deprecated fields are still filled, and the facets are inherited in list output.
+	assert.Equal(t, []string{"BASIC_INFO"}, schema.XTFFacets)
+	assert.Equal(t, []interface{}{"tf://types/person"}, (*schema.Properties)["person"].XTFAcceptedObjects)
+	assert.Equal(t, []string{"BASIC_INFO"}, result.XTFFacets)
+	for _, hierarchy := range []bool{true, false} {
+		list := result.ToList(hierarchy)
+		assert.Equal(t, []string{"BASIC_INFO"}, list.XTFFacets)
+		for _, detail := range list.Details {
+			assert.Equal(t, []string{"BASIC_INFO"}, detail.XTFFacets, "Expected %s to inherit the facets", detail.EvaluationPath)
+		}
+	}
+
+	// The keywords are kept when the schema is serialized.
+	data, err := json.Marshal(schema)
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), `"x-tf-facets":["BASIC_INFO"]`)
+	assert.Contains(t, string(data), `"x-tf-accepted-objects":["tf://types/person"]`)
+
+	// The keywords can be replaced.
+	schema, err = NewCompiler().RegisterKeyword("x-tf-facets", knownKeyword{}).Compile(schemaJSON)
+	assert.NoError(t, err)
+	result = schema.Validate(map[string]interface{}{})
+	assert.True(t, result.IsValid())
+	assert.Empty(t, filterAnnotations(result.AnnotationsAt(""), "x-tf-facets"))
+}
+
+// filterAnnotations returns the annotations of the given keyword.
+func filterAnnotations(annotations []Annotation, keyword string) []Annotation {
+	var filtered []Annotation
+	for _, annotation := range annotations {
+		if annotation.Keyword == keyword {
+			filtered = append(filtered, annotation)
+		}
+	}
+	return filtered
+}
@@ -0,0 +1,99 @@
+package jsonschema
+
+import (
+	"embed"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/goccy/go-json"
+)
+
+// MetaSchemaURL is the URI of the JSON Schema 2020-12 meta-schema.
+const MetaSchemaURL = "https://json-schema.org/draft/2020-12/schema"
+
+// metaSchemaBaseURL is the URL prefix under which the official meta-schemas are published.
+const metaSchemaBaseURL = "https://json-schema.org/"
+
+// metaSchemasFS holds offline copies of the 2020-12 meta-schema and its vocabulary meta-schemas,
+// stored under the path of their URL with a ".json" suffix.
+//
+//go:embed metaschemas
+var metaSchemasFS embed.FS
+
+// embeddedMetaSchema returns the embedded document published at the given URL, if any.
+func embeddedMetaSchema(url string) ([]byte, bool) {
+	id, _ := splitRef(url)
+	if !strings.HasPrefix(id, metaSchemaBaseURL) {
+		return nil, false
+	}
+
+	data, err := metaSchemasFS.ReadFile("metaschemas/" + strings.TrimPrefix(id, metaSchemaBaseURL) + ".json")
+	if err != nil {
+		return nil, false
+	}
+	return data, true
+}
+
+var (
+	metaSchemaOnce     sync.Once
+	compiledMetaSchema *Schema
+	metaSchemaErr      error
+)
+
+// getMetaSchema returns the 2020-12 meta-schema, compiled once from the embedded copies by a
+// dedicated compiler, so that checking schemas never depends on the settings of the caller.
+func getMetaSchema() (*Schema, error) {
+	metaSchemaOnce.Do(func() {
+		compiledMetaSchema, metaSchemaErr = NewCompiler().GetSchema(MetaSchemaURL)
+	})
+	return compiledMetaSchema, metaSchemaErr
+}
+
+// ValidateSchema checks a raw schema document against the 2020-12 meta-schema and its vocabularies,
+// and returns the evaluation result. The result can be rendered and localized like any other
+// validation result. An error is only returned when the document is not valid JSON.
+func (c *Compiler) ValidateSchema(jsonSchema []byte) (*EvaluationResult, error) {
+	var document interface{}
+	if err := json.Unmarshal(jsonSchema, &document); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrJSONUnmarshalError, err)
+	}
+
+	metaSchema, err := getMetaSchema()
+	if err != nil {
+		return nil, err
+	}
+	return metaSchema.Validate(document), nil
+}
+
+// InvalidSchemaError is returned by Compile when schema validation is enabled and a schema does
+// not conform to the meta-schema. Result holds the located errors reported by the meta-schema.
+type InvalidSchemaError struct {
+	URI    string            // URI of the compiled schema, if any.
+	Result *EvaluationResult // Result of validating the schema against the meta-schema.
+}
+
+// Error returns a description of the keywords that do not conform to the meta-schema.
+func (e *InvalidSchemaError) Error() string {
+	var messages []string
+	for _, violation := range e.Result.Violations() {
+		location := violation.InstancePath
+		if location == "" {
+			location = "/"
+		}
+		messages = append(messages, location+": "+violation.Message)
+	}
+	sort.Strings(messages)
+
+	prefix := ErrInvalidSchema.Error()
+	if e.URI != "" {
+		prefix += " " + e.URI
+	}
+	return prefix + ": " + strings.Join(messages, "; ")
+}
+
+// Unwrap returns ErrInvalidSchema, so the error can be matched with errors.Is.
+func (e *InvalidSchemaError) Unwrap() error {
+	return ErrInvalidSchema
+}
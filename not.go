@@ -15,13 +15,9 @@ func evaluateNot(schema *Schema, instance interface{}, evaluatedProps map[string
 		return nil, nil // No 'not' constraints to validate against
 	}
 
-	result, _, _ := schema.Not.evaluate(instance, dynamicScope)
+	result, _, _ := schema.Not.evaluateIn(instance, "/not", dynamicScope)
 
 	if result != nil {
-		result.SetEvaluationPath("/oneOf").
-			SetSchemaLocation(schema.GetSchemaLocation("/oneOf")).
-			SetInstanceLocation("")
-
 		if result.IsValid() {
 			return result, NewEvaluationError("not", "not_schema_mismatch", "Value should not match the not schema")
 		}
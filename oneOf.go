@@ -1,7 +1,6 @@
 package jsonschema
 
 import (
-	"fmt"
 	"strconv"
 	"strings"
 )
@@ -26,13 +25,13 @@ func evaluateOneOf(schema *Schema, instance interface{}, evaluatedProps map[stri
 	var tempEvaluatedItems map[int]bool
 
 	for i, subSchema := range schema.OneOf {
+		if dynamicScope.stops(len(valid_indexs) > 1) {
+			break // The remaining subschemas cannot make the instance valid again.
+		}
 		if subSchema != nil {
-			result, schemaEvaluatedProps, schemaEvaluatedItems := subSchema.evaluate(instance, dynamicScope)
+			result, schemaEvaluatedProps, schemaEvaluatedItems := subSchema.evaluateIn(instance, "/oneOf/"+strconv.Itoa(i), dynamicScope)
 			if result != nil {
-				results = append(results, result.SetEvaluationPath(fmt.Sprintf("/oneOf/%d", i)).
-					SetSchemaLocation(schema.GetSchemaLocation(fmt.Sprintf("/oneOf/%d", i))).
-					SetInstanceLocation(""),
-				)
+				results = append(results, result)
 
 				if result.IsValid() {
 					valid_indexs = append(valid_indexs, strconv.Itoa(i))
@@ -0,0 +1,276 @@
+package jsonschema
+
+import (
+	"sort"
+
+	"github.com/goccy/go-json"
+	"github.com/kaptinlin/go-i18n"
+)
+
+// OutputUnit is a node of the output formats of JSON Schema draft 2020-12, see
+// https://json-schema.org/draft/2020-12/json-schema-core#name-output-formatting. It reports the outcome
+// of a keyword, or of a subschema, applied to a location of the instance. Units of an invalid parent
+// are listed in Errors, and units of a valid one in Annotations.
+type OutputUnit struct {
+	Valid                   bool          `json:"valid"`
+	KeywordLocation         string        `json:"keywordLocation"`                   // Keywords followed from the root schema, through references.
+	AbsoluteKeywordLocation string        `json:"absoluteKeywordLocation,omitempty"` // URI of the keyword, omitted if the schema has none.
+	InstanceLocation        string        `json:"instanceLocation"`                  // JSON Pointer of the value within the instance.
+	Error                   string        `json:"error,omitempty"`
+	Errors                  []*OutputUnit `json:"errors,omitempty"`
+	Annotation              interface{}   `json:"annotation,omitempty"`
+	Annotations             []*OutputUnit `json:"annotations,omitempty"`
+}
+
+// MarshalJSON implements json.Marshaler. The unit is encoded without indentation, which the indenting
+// encoder of github.com/goccy/go-json then applies, as that encoder does not terminate on the two
+// recursive lists of OutputUnit.
+func (u *OutputUnit) MarshalJSON() ([]byte, error) {
+	type outputUnit OutputUnit
+	return json.Marshal((*outputUnit)(u))
+}
+
+// add appends a nested unit to the errors or the annotations of u, depending on the validity of u.
+func (u *OutputUnit) add(unit *OutputUnit) {
+	if u.Valid {
+		u.Annotations = append(u.Annotations, unit)
+	} else {
+		u.Errors = append(u.Errors, unit)
+	}
+}
+
+// units returns the nested units of u.
+func (u *OutputUnit) units() []*OutputUnit {
+	if u.Valid {
+		return u.Annotations
+	}
+	return u.Errors
+}
+
+// ToBasic converts the result to the "basic" output format: a flat list of the failing keywords with
+// their error, or, for a valid instance, of the annotations.
+func (e *EvaluationResult) ToBasic() *OutputUnit {
+	return e.ToLocalizeBasic(nil)
+}
+
+// ToLocalizeBasic converts the result to the "basic" output format with localized error messages.
+func (e *EvaluationResult) ToLocalizeBasic(localizer *i18n.Localizer) *OutputUnit {
+	root := (&outputBuilder{localizer: localizer}).unit(e, true)
+
+	basic := &OutputUnit{Valid: root.Valid, KeywordLocation: root.KeywordLocation, AbsoluteKeywordLocation: root.AbsoluteKeywordLocation}
+	var flatten func(unit *OutputUnit)
+	flatten = func(unit *OutputUnit) {
+		if unit.Error != "" || unit.Annotation != nil {
+			basic.add(&OutputUnit{
+				Valid:                   unit.Valid,
+				KeywordLocation:         unit.KeywordLocation,
+				AbsoluteKeywordLocation: unit.AbsoluteKeywordLocation,
+				InstanceLocation:        unit.InstanceLocation,
+				Error:                   unit.Error,
+				Annotation:              unit.Annotation,
+			})
+		}
+		for _, nested := range unit.units() {
+			flatten(nested)
+		}
+	}
+	flatten(root)
+	return basic
+}
+
+// ToDetailed converts the result to the "detailed" output format: a hierarchy following the schema,
+// holding the failing keywords or, for a valid instance, the annotations, in which a unit with a single
+// nested unit is replaced by that unit.
+func (e *EvaluationResult) ToDetailed() *OutputUnit {
+	return e.ToLocalizeDetailed(nil)
+}
+
+// ToLocalizeDetailed converts the result to the "detailed" output format with localized error messages.
+func (e *EvaluationResult) ToLocalizeDetailed(localizer *i18n.Localizer) *OutputUnit {
+	root := (&outputBuilder{localizer: localizer}).unit(e, true)
+
+	var condense func(unit *OutputUnit) *OutputUnit
+	condense = func(unit *OutputUnit) *OutputUnit {
+		units := unit.units()
+		for i, nested := range units {
+			units[i] = condense(nested)
+		}
+		if len(units) == 1 {
+			return units[0]
+		}
+		return unit
+	}
+	for i, nested := range root.units() {
+		root.units()[i] = condense(nested)
+	}
+	return root
+}
+
+// ToVerbose converts the result to the "verbose" output format: the whole hierarchy of the failing
+// keywords and of the subschemas evaluated, whether they passed or failed.
+func (e *EvaluationResult) ToVerbose() *OutputUnit {
+	return e.ToLocalizeVerbose(nil)
+}
+
+// ToLocalizeVerbose converts the result to the "verbose" output format with localized error messages.
+func (e *EvaluationResult) ToLocalizeVerbose(localizer *i18n.Localizer) *OutputUnit {
+	return (&outputBuilder{localizer: localizer, verbose: true}).unit(e, true)
+}
+
+// outputBuilder builds the output units of an evaluation result.
+type outputBuilder struct {
+	localizer *i18n.Localizer
+	verbose   bool // Whether to keep the keywords and subschemas that do not explain the outcome.
+}
+
+// outputGroup gathers the errors and the subschema results of a keyword of a schema.
+type outputGroup struct {
+	keyword string
+	errors  []*EvaluationError
+	details []*EvaluationResult
+}
+
+// unit returns the unit of a schema result. The annotations of the schema are kept if annotate is set,
+// that is if no enclosing schema failed.
+func (b *outputBuilder) unit(result *EvaluationResult, annotate bool) *OutputUnit {
+	s := result.schema
+	unit := &OutputUnit{
+		Valid:                   result.Valid,
+		KeywordLocation:         result.EvaluationPath,
+		AbsoluteKeywordLocation: s.absoluteLocation(""),
+		InstanceLocation:        result.InstanceLocation,
+	}
+	annotate = annotate && result.Valid
+
+	// A false schema fails by itself rather than through one of its keywords.
+	if s.Boolean != nil {
+		for _, err := range result.errorList {
+			unit.Error = err.Localize(b.localizer)
+		}
+		return unit
+	}
+
+	var groups []*outputGroup
+	group := func(keyword string) *outputGroup {
+		for _, g := range groups {
+			if g.keyword == keyword {
+				return g
+			}
+		}
+		g := &outputGroup{keyword: keyword}
+		groups = append(groups, g)
+		return g
+	}
+	for _, err := range result.errorList {
+		keyword := subschemasKeyword(err)
+		if keyword == "" {
+			keyword = err.keyword
+		}
+		g := group(keyword)
+		g.errors = append(g.errors, err)
+	}
+	for _, detail := range result.Details {
+		g := group(s.detailKeyword(detail.schema))
+		g.details = append(g.details, detail)
+	}
+
+	// Subschemas of object-valued keywords are evaluated in no particular order, so their results are
+	// listed in the order of the subschemas within the schema.
+	order := map[*Schema]int{}
+	for i, sub := range s.subschemas() {
+		order[sub.schema] = i
+	}
+	for _, g := range groups {
+		sort.SliceStable(g.details, func(i, j int) bool {
+			return order[g.details[i].schema] < order[g.details[j].schema]
+		})
+	}
+
+	for _, g := range groups {
+		if keywordUnit := b.keywordUnit(result, g, annotate); keywordUnit != nil {
+			unit.add(keywordUnit)
+		}
+		for _, err := range g.errors[min(1, len(g.errors)):] {
+			unit.add(b.errorUnit(result, g.keyword, err))
+		}
+	}
+
+	if annotate {
+		keywords := make([]string, 0, len(result.Annotations))
+		for keyword := range result.Annotations {
+			keywords = append(keywords, keyword)
+		}
+		sort.Strings(keywords)
+
+		for _, keyword := range keywords {
+			path := "/" + escapeJSONPointerSegment(keyword)
+			unit.add(&OutputUnit{
+				Valid:                   true,
+				KeywordLocation:         result.EvaluationPath + path,
+				AbsoluteKeywordLocation: s.absoluteLocation(path),
+				InstanceLocation:        result.InstanceLocation,
+				Annotation:              result.Annotations[keyword],
+			})
+		}
+	}
+	return unit
+}
+
+// keywordUnit returns the unit of the keyword of a group, holding the units of its subschemas, or nil if
+// the keyword is left out of the output.
+func (b *outputBuilder) keywordUnit(result *EvaluationResult, g *outputGroup, annotate bool) *OutputUnit {
+	var unit *OutputUnit
+	explained := false
+	if len(g.errors) > 0 {
+		unit = b.errorUnit(result, g.keyword, g.errors[0])
+		explained = subschemasKeyword(g.errors[0]) != ""
+	} else {
+		path := "/" + escapeJSONPointerSegment(g.keyword)
+		unit = &OutputUnit{
+			Valid:                   true,
+			KeywordLocation:         result.EvaluationPath + path,
+			AbsoluteKeywordLocation: result.schema.absoluteLocation(path),
+			InstanceLocation:        result.InstanceLocation,
+		}
+	}
+
+	for _, detail := range g.details {
+		switch {
+		case b.verbose:
+		case !unit.Valid && (!explained || detail.Valid):
+			continue
+		case unit.Valid && !detail.Valid:
+			continue
+		}
+
+		nested := b.unit(detail, annotate && unit.Valid)
+		if b.verbose || !nested.Valid || len(nested.Annotations) > 0 {
+			unit.add(nested)
+		}
+	}
+
+	if !b.verbose && unit.Valid && len(unit.Annotations) == 0 {
+		return nil
+	}
+	return unit
+}
+
+// errorUnit returns the unit of the keyword of the schema of result failing with err.
+func (b *outputBuilder) errorUnit(result *EvaluationResult, keyword string, err *EvaluationError) *OutputUnit {
+	path := "/" + escapeJSONPointerSegment(keyword)
+	return &OutputUnit{
+		KeywordLocation:         result.EvaluationPath + path,
+		AbsoluteKeywordLocation: result.schema.absoluteLocation(path),
+		InstanceLocation:        result.InstanceLocation,
+		Error:                   err.Localize(b.localizer),
+	}
+}
+
+// absoluteLocation returns the URI of the keyword of s at the given path, see GetSchemaLocation, or ""
+// if s has no URI.
+func (s *Schema) absoluteLocation(path string) string {
+	if s == nil || s.getScopeSchema().GetSchemaURI() == "" {
+		return ""
+	}
+	return s.GetSchemaLocation(path)
+}
@@ -0,0 +1,141 @@
+package jsonschema
+
+import (
+	"testing"
+
+	"github.com/goccy/go-json"
+	"github.com/test-go/testify/assert"
+)
+
+// locations lists the keyword and instance locations of units, with their error or annotation.
+func locations(units []*OutputUnit) []string {
+	var list []string
+	for _, unit := range units {
+		entry := unit.KeywordLocation + " " + unit.InstanceLocation
+		if unit.Error != "" {
+			entry += ": " + unit.Error
+		}
+		if unit.Annotation != nil {
+			encoded, _ := json.Marshal(unit.Annotation)
+			entry += ": " + string(encoded)
+		}
+		list = append(list, entry)
+	}
+	return list
+}
+
+func TestOutputFormats(t *testing.T) {
+	schema, err := NewCompiler().Compile([]byte(`{
+		"$id": "https://example.com/polygon",
+		"$defs": {
+			"point": {
+				"type": "object",
+				"properties": {
+					"x": {"type": "number"},
+					"y": {"type": "number"}
+				},
+				"additionalProperties": false
+			}
+		},
+		"type": "array",
+		"items": {"$ref": "#/$defs/point"},
+		"minItems": 3
+	}`))
+	assert.NoError(t, err)
+
+	result := schema.Validate([]interface{}{
+		map[string]interface{}{"x": 2.5, "y": 1.3},
+		map[string]interface{}{"x": 1, "y": "a", "z/~": 6.7},
+	})
+
+	t.Run("basic", func(t *testing.T) {
+		basic := result.ToBasic()
+		assert.False(t, basic.Valid)
+		assert.Empty(t, basic.Annotations)
+		assert.Equal(t, []string{
+			"/items : Item at index 1 does not match the schema",
+			"/items/$ref /1: Value does not match the reference schema",
+			"/items/$ref/properties /1: Property 'y' does not match the schema",
+			"/items/$ref/properties/y/type /1/y: Value is string but should be number",
+			"/items/$ref/additionalProperties /1: Additional property 'z/~' does not match the schema",
+			"/items/$ref/additionalProperties /1/z~1~0: No values are allowed because the schema is set to 'false'",
+			"/minItems : Value should have at least 3 items",
+		}, locations(basic.Errors))
+		assert.Equal(t, "https://example.com/polygon#/$defs/point/properties/y/type", basic.Errors[3].AbsoluteKeywordLocation)
+	})
+
+	t.Run("detailed", func(t *testing.T) {
+		detailed := result.ToDetailed()
+		assert.Equal(t, []string{"/items/$ref /1", "/minItems : Value should have at least 3 items"}, locations(detailed.Errors))
+
+		point := detailed.Errors[0]
+		assert.Equal(t, "https://example.com/polygon#/$defs/point", point.AbsoluteKeywordLocation)
+		assert.Equal(t, []string{
+			"/items/$ref/properties/y/type /1/y: Value is string but should be number",
+			"/items/$ref/additionalProperties /1/z~1~0: No values are allowed because the schema is set to 'false'",
+		}, locations(point.Errors))
+	})
+
+	t.Run("verbose", func(t *testing.T) {
+		verbose := result.ToVerbose()
+		assert.Equal(t, []string{
+			"/items : Item at index 1 does not match the schema",
+			"/minItems : Value should have at least 3 items",
+		}, locations(verbose.Errors))
+
+		// The verbose output keeps the item that passed.
+		assert.True(t, verbose.Errors[0].Errors[0].Valid)
+		assert.Equal(t, "/0", verbose.Errors[0].Errors[0].InstanceLocation)
+		item := verbose.Errors[0].Errors[1]
+		assert.Equal(t, []string{"/items/$ref /1: Value does not match the reference schema"}, locations(item.Errors))
+		point := item.Errors[0].Errors[0]
+		assert.Equal(t, "/items/$ref", point.KeywordLocation)
+		assert.Equal(t, "https://example.com/polygon#/$defs/point", point.AbsoluteKeywordLocation)
+
+		properties := point.Errors[0]
+		assert.Equal(t, []string{"/items/$ref/properties/x /1/x", "/items/$ref/properties/y /1/y"}, locations(properties.Errors))
+		assert.True(t, properties.Errors[0].Valid)
+		assert.False(t, properties.Errors[1].Valid)
+	})
+
+	t.Run("annotations", func(t *testing.T) {
+		schema, err := NewCompiler().Compile([]byte(`{
+			"title": "point",
+			"properties": {
+				"x": {"readOnly": true},
+				"y": {"type": "number", "readOnly": true}
+			}
+		}`))
+		assert.NoError(t, err)
+
+		basic := schema.Validate(map[string]interface{}{"x": 1, "y": 2}).ToBasic()
+		assert.True(t, basic.Valid)
+		assert.Empty(t, basic.Errors)
+		assert.Equal(t, []string{
+			"/properties/x/readOnly /x: true",
+			"/properties/y/readOnly /y: true",
+			"/properties : [\"x\",\"y\"]",
+			"/title : \"point\"",
+		}, locations(basic.Annotations))
+
+		basic = schema.Validate(map[string]interface{}{"x": 1, "y": "2"}).ToBasic()
+		assert.False(t, basic.Valid)
+		assert.Empty(t, basic.Annotations)
+		assert.Equal(t, []string{
+			"/properties : Property 'y' does not match the schema",
+			"/properties/y/type /y: Value is string but should be number",
+		}, locations(basic.Errors))
+	})
+
+	t.Run("json", func(t *testing.T) {
+		encoded, err := json.MarshalIndent(result.ToDetailed(), "", "  ")
+		assert.NoError(t, err)
+
+		var output map[string]interface{}
+		assert.NoError(t, json.Unmarshal(encoded, &output))
+		assert.Equal(t, false, output["valid"])
+		assert.Equal(t, "", output["keywordLocation"])
+		assert.Len(t, output["errors"], 2)
+		assert.NotContains(t, output, "annotations")
+	})
+}
@@ -1,7 +1,5 @@
 package jsonschema
 
-import "regexp"
-
 // EvaluatePattern checks if the string data matches the regular expression specified in the "pattern" schema attribute.
 // According to the JSON Schema Draft 2020-12:
 //   - The value of "pattern" must be a string that should be a valid regular expression, according to the ECMA-262 regular expression dialect.
@@ -14,13 +12,17 @@ import "regexp"
 // Reference: https://json-schema.org/draft/2020-12/json-schema-validation#name-pattern
 func evaluatePattern(schema *Schema, instance string) *EvaluationError {
 	if schema.Pattern != nil {
-		// Compile the regular expression from the pattern.
-		regExp, err := regexp.Compile(*schema.Pattern)
-		if err != nil {
-			// Handle regular expression compilation errors.
-			return NewEvaluationError("pattern", "invalid_pattern", "Invalid regular expression pattern {pattern}", map[string]interface{}{
-				"pattern": *schema.Pattern,
-			})
+		// The regular expression is compiled once during schema initialization.
+		regExp := schema.compiledPattern
+		if regExp == nil {
+			// Handle schemas that were not initialized by a compiler.
+			var err error
+			regExp, err = schema.compiler.compileRegexp(*schema.Pattern)
+			if err != nil {
+				return NewEvaluationError("pattern", "invalid_pattern", "Invalid regular expression pattern {pattern}", map[string]interface{}{
+					"pattern": *schema.Pattern,
+				})
+			}
 		}
 
 		// Check if the regular expression matches the string value.
@@ -2,27 +2,10 @@ package jsonschema
 
 import (
 	"fmt"
-	"regexp"
 	"slices"
 	"strings"
 )
 
-// Initialize or Load Schema
-func (s *Schema) compilePatterns() {
-	if s.PatternProperties == nil {
-		return // No patterns to compile if the map is nil
-	}
-
-	s.compiledPatterns = make(map[string]*regexp.Regexp)
-	// Since s.PatternProperties is a pointer to a SchemaMap, we dereference it here
-	for pattern := range *s.PatternProperties {
-		regex, err := regexp.Compile(pattern)
-		if err == nil {
-			s.compiledPatterns[pattern] = regex
-		}
-	}
-}
-
 // EvaluatePatternProperties checks if properties in the data object that match regex patterns conform to the schemas specified in the schema's patternProperties attribute.
 // According to the JSON Schema Draft 2020-12:
 //   - Each property name in "patternProperties" must be a valid regex and each property value must be a valid JSON Schema.
@@ -31,7 +14,7 @@ func (s *Schema) compilePatterns() {
 // This function ensures that properties which match the patterns validate accordingly and aids the behavior of "additionalProperties" and "unevaluatedProperties".
 //
 // Reference: https://json-schema.org/draft/2020-12/json-schema-core#name-patternproperties
-func evaluatePatternProperties(schema *Schema, object map[string]interface{}, evaluatedProps map[string]bool, evaluatedItems map[int]bool, dynamicScope *DynamicScope) ([]*EvaluationResult, *EvaluationError) {
+func evaluatePatternProperties(schema *Schema, object map[string]interface{}, result *EvaluationResult, evaluatedProps map[string]bool, evaluatedItems map[int]bool, dynamicScope *DynamicScope) ([]*EvaluationResult, *EvaluationError) {
 	if schema.PatternProperties == nil {
 		return nil, nil // No patternProperties defined, nothing to do.
 	}
@@ -39,42 +22,45 @@ func evaluatePatternProperties(schema *Schema, object map[string]interface{}, ev
 	// invalid_regex  := []string{}
 	invalid_properties := []string{}
 	results := []*EvaluationResult{}
+	matched := []string{}
 
 	// Loop over each pattern in the PatternProperties map.
 	for patternKey, patternSchema := range *schema.PatternProperties {
-		// Get from the compiled patterns map, if not found, compile the pattern
+		if dynamicScope.stops(len(invalid_properties) > 0) {
+			break
+		}
+		// Patterns are compiled during schema initialization.
 		regex, ok := schema.compiledPatterns[patternKey]
 		if !ok {
-			var err error
-			regex, err = regexp.Compile(patternKey)
-			if err != nil {
-				// invalid_regex = append(invalid_regex, patternKey)
-				continue
-			}
-			schema.compiledPatterns[patternKey] = regex
+			continue
 		}
 
 		// Check each property in the object against the compiled regex.
 		for propName, propValue := range object {
+			if dynamicScope.stops(len(invalid_properties) > 0) {
+				break
+			}
 			if regex.MatchString(propName) {
-				evaluatedProps[propName] = true
+				if evaluatedProps != nil {
+					evaluatedProps[propName] = true
+				}
+				if dynamicScope.annotates() && !slices.Contains(matched, propName) {
+					matched = append(matched, propName)
+				}
 
 				// Evaluate the property value directly using the associated schema or boolean.
-				result, _, _ := patternSchema.evaluate(propValue, dynamicScope)
-				if result != nil {
-					result.SetEvaluationPath(fmt.Sprintf("/patternProperties/%s", propName)).
-						SetSchemaLocation(schema.GetSchemaLocation(fmt.Sprintf("/patternProperties/%s", propName))).
-						SetInstanceLocation(fmt.Sprintf("/%s", propName))
-
-					results = append(results, result)
+				propResult, _, _ := patternSchema.evaluateAt(propValue, "/patternProperties/"+escapeJSONPointerSegment(patternKey), propName, dynamicScope)
+				if propResult != nil {
+					results = append(results, propResult)
 
-					if !result.IsValid() && !slices.Contains(invalid_properties, propName) {
+					if !propResult.IsValid() && !slices.Contains(invalid_properties, propName) {
 						invalid_properties = append(invalid_properties, propName)
 					}
 				}
 			}
 		}
 	}
+	annotateNames(result, "patternProperties", matched)
 
 	if len(invalid_properties) == 1 {
 		return results, NewEvaluationError("properties", "pattern_property_mismatch", "Property {property} does not match the pattern schema", map[string]interface{}{
@@ -0,0 +1,330 @@
+package jsonschema
+
+import "strings"
+
+// keywordEvaluator evaluates a keyword, or a group of keywords validated together, of a schema against
+// an instance. It records the errors and the subschema results of the keywords in result, and marks the
+// properties and items they evaluated, unless the maps are nil as no unevaluatedProperties or
+// unevaluatedItems keyword needs them.
+type keywordEvaluator func(s *Schema, instance interface{}, result *EvaluationResult, evaluatedProps map[string]bool, evaluatedItems map[int]bool, dynamicScope *DynamicScope)
+
+// evaluationPlan is the compiled form of a schema: the evaluators of the keywords it holds, in the order
+// they are evaluated, so that the keywords a schema does not use cost nothing at validation time.
+type evaluationPlan struct {
+	evaluators      []keywordEvaluator
+	tracksEvaluated bool   // Whether the schema needs the properties and items evaluated by its other keywords.
+	location        string // Location of the schema, see GetSchemaLocation.
+}
+
+// compilePlan returns the evaluation plan of s, which only depends on the keywords of s and on the
+// vocabularies of its dialect. The subschemas and references are looked up when evaluating.
+func (s *Schema) compilePlan() *evaluationPlan {
+	plan := &evaluationPlan{location: s.GetSchemaLocation("")}
+	add := func(evaluator keywordEvaluator) {
+		plan.evaluators = append(plan.evaluators, evaluator)
+	}
+
+	if s.Boolean != nil {
+		add(func(s *Schema, instance interface{}, result *EvaluationResult, evaluatedProps map[string]bool, evaluatedItems map[int]bool, dynamicScope *DynamicScope) {
+			if err := s.evaluateBoolean(instance, evaluatedProps, evaluatedItems); err != nil {
+				result.AddError(err)
+			}
+		})
+		return plan
+	}
+
+	// Keywords of vocabularies that are not active in the schema's dialect are ignored.
+	applicator := s.hasVocabulary(VocabularyApplicator)
+	validation := s.hasVocabulary(VocabularyValidation)
+	unevaluated := s.hasVocabulary(VocabularyUnevaluated)
+
+	if s.Ref != "" {
+		add(evaluateRef)
+	}
+	if s.DynamicRef != "" {
+		add(evaluateDynamicRef)
+	}
+
+	// Meta-data keywords asserted by the direction of the validation, see WithDirection
+	if s.hasVocabulary(VocabularyMetaData) && (s.ReadOnly != nil && *s.ReadOnly || s.WriteOnly != nil && *s.WriteOnly) {
+		add(func(s *Schema, instance interface{}, result *EvaluationResult, evaluatedProps map[string]bool, evaluatedItems map[int]bool, dynamicScope *DynamicScope) {
+			record(result, nil, evaluateDirection(s, dynamicScope.direction))
+		})
+	}
+
+	// Validation keywords for any instance type
+	if validation && s.Type != nil {
+		add(func(s *Schema, instance interface{}, result *EvaluationResult, evaluatedProps map[string]bool, evaluatedItems map[int]bool, dynamicScope *DynamicScope) {
+			record(result, nil, evaluateType(s, instance))
+		})
+	}
+	if validation && s.Enum != nil {
+		enum := newJSONSet(s.Enum)
+		add(func(s *Schema, instance interface{}, result *EvaluationResult, evaluatedProps map[string]bool, evaluatedItems map[int]bool, dynamicScope *DynamicScope) {
+			record(result, nil, evaluateEnum(s, instance, enum))
+		})
+	}
+	if validation && s.Const != nil {
+		add(func(s *Schema, instance interface{}, result *EvaluationResult, evaluatedProps map[string]bool, evaluatedItems map[int]bool, dynamicScope *DynamicScope) {
+			record(result, nil, evaluateConst(s, instance))
+		})
+	}
+
+	// Keywords for applying subschemas with logical operations
+	if applicator && s.AllOf != nil {
+		add(func(s *Schema, instance interface{}, result *EvaluationResult, evaluatedProps map[string]bool, evaluatedItems map[int]bool, dynamicScope *DynamicScope) {
+			allOfResults, allOfError := evaluateAllOf(s, instance, evaluatedProps, evaluatedItems, dynamicScope)
+			record(result, allOfResults, allOfError)
+		})
+	}
+	if applicator && s.AnyOf != nil {
+		add(func(s *Schema, instance interface{}, result *EvaluationResult, evaluatedProps map[string]bool, evaluatedItems map[int]bool, dynamicScope *DynamicScope) {
+			anyOfResults, anyOfError := evaluateAnyOf(s, instance, evaluatedProps, evaluatedItems, dynamicScope)
+			record(result, anyOfResults, anyOfError)
+		})
+	}
+	if applicator && s.OneOf != nil {
+		add(func(s *Schema, instance interface{}, result *EvaluationResult, evaluatedProps map[string]bool, evaluatedItems map[int]bool, dynamicScope *DynamicScope) {
+			oneOfResults, oneOfError := evaluateOneOf(s, instance, evaluatedProps, evaluatedItems, dynamicScope)
+			record(result, oneOfResults, oneOfError)
+		})
+	}
+	if applicator && s.Not != nil {
+		add(func(s *Schema, instance interface{}, result *EvaluationResult, evaluatedProps map[string]bool, evaluatedItems map[int]bool, dynamicScope *DynamicScope) {
+			notResult, notError := evaluateNot(s, instance, evaluatedProps, evaluatedItems, dynamicScope)
+			recordOne(result, notResult, notError)
+		})
+	}
+
+	// Keywords for applying subschemas with conditional logic
+	if applicator && (s.If != nil || s.Then != nil || s.Else != nil) {
+		add(func(s *Schema, instance interface{}, result *EvaluationResult, evaluatedProps map[string]bool, evaluatedItems map[int]bool, dynamicScope *DynamicScope) {
+			conditionalResults, conditionalError := evaluateConditional(s, instance, evaluatedProps, evaluatedItems, dynamicScope)
+			record(result, conditionalResults, conditionalError)
+		})
+	}
+
+	// Keywords for arrays
+	if len(s.PrefixItems) > 0 || s.Items != nil || s.Contains != nil || s.MaxContains != nil || s.MinContains != nil ||
+		s.MaxItems != nil || s.MinItems != nil || s.UniqueItems != nil {
+		add(func(s *Schema, instance interface{}, result *EvaluationResult, evaluatedProps map[string]bool, evaluatedItems map[int]bool, dynamicScope *DynamicScope) {
+			arrayResults, arrayErrors := evaluateArray(s, instance, result, evaluatedProps, evaluatedItems, dynamicScope)
+			record(result, arrayResults, arrayErrors...)
+		})
+	}
+
+	// Validation keywords for numbers and strings, whose errors stop at the first one in fail-fast mode
+	if validation && (s.MultipleOf != nil || s.Maximum != nil || s.ExclusiveMaximum != nil || s.Minimum != nil || s.ExclusiveMinimum != nil) {
+		add(func(s *Schema, instance interface{}, result *EvaluationResult, evaluatedProps map[string]bool, evaluatedItems map[int]bool, dynamicScope *DynamicScope) {
+			recordFirst(result, evaluateNumeric(s, instance), dynamicScope)
+		})
+	}
+	if validation && (s.MaxLength != nil || s.MinLength != nil || s.Pattern != nil) {
+		add(func(s *Schema, instance interface{}, result *EvaluationResult, evaluatedProps map[string]bool, evaluatedItems map[int]bool, dynamicScope *DynamicScope) {
+			recordFirst(result, evaluateString(s, instance), dynamicScope)
+		})
+	}
+
+	if s.Format != nil {
+		add(func(s *Schema, instance interface{}, result *EvaluationResult, evaluatedProps map[string]bool, evaluatedItems map[int]bool, dynamicScope *DynamicScope) {
+			unknownFormat, formatError := evaluateFormat(s, instance)
+			record(result, nil, formatError)
+			if dynamicScope.annotates() {
+				result.AddAnnotation("format", *s.Format)
+				if unknownFormat {
+					result.AddAnnotation("unknownFormat", *s.Format)
+				}
+			}
+		})
+	}
+
+	// Keywords for objects
+	if s.Properties != nil || s.PatternProperties != nil || s.AdditionalProperties != nil || s.PropertyNames != nil ||
+		s.MaxProperties != nil || s.MinProperties != nil || len(s.Required) > 0 || len(s.DependentRequired) > 0 {
+		add(func(s *Schema, instance interface{}, result *EvaluationResult, evaluatedProps map[string]bool, evaluatedItems map[int]bool, dynamicScope *DynamicScope) {
+			objectResults, objectErrors := evaluateObject(s, instance, result, evaluatedProps, evaluatedItems, dynamicScope)
+			record(result, objectResults, objectErrors...)
+		})
+	}
+	if applicator && s.DependentSchemas != nil {
+		add(func(s *Schema, instance interface{}, result *EvaluationResult, evaluatedProps map[string]bool, evaluatedItems map[int]bool, dynamicScope *DynamicScope) {
+			dependentSchemasResults, dependentSchemasError := evaluateDependentSchemas(s, instance, evaluatedProps, evaluatedItems, dynamicScope)
+			record(result, dependentSchemasResults, dependentSchemasError)
+		})
+	}
+
+	// Custom keywords registered on the compiler, evaluated before the unevaluated* keywords
+	// so that the properties and items they mark as evaluated are taken into account.
+	if len(s.keywords) > 0 {
+		add(evaluateKeywords)
+	}
+
+	if unevaluated && s.UnevaluatedProperties != nil {
+		plan.tracksEvaluated = true
+		add(func(s *Schema, instance interface{}, result *EvaluationResult, evaluatedProps map[string]bool, evaluatedItems map[int]bool, dynamicScope *DynamicScope) {
+			unevaluatedPropertiesResults, unevaluatedPropertiesError := evaluateUnevaluatedProperties(s, instance, result, evaluatedProps, evaluatedItems, dynamicScope)
+			record(result, unevaluatedPropertiesResults, unevaluatedPropertiesError)
+		})
+	}
+	if unevaluated && s.UnevaluatedItems != nil {
+		plan.tracksEvaluated = true
+		add(func(s *Schema, instance interface{}, result *EvaluationResult, evaluatedProps map[string]bool, evaluatedItems map[int]bool, dynamicScope *DynamicScope) {
+			unevaluatedItemsResults, unevaluatedItemsError := evaluateUnevaluatedItems(s, instance, result, evaluatedProps, evaluatedItems, dynamicScope)
+			record(result, unevaluatedItemsResults, unevaluatedItemsError)
+		})
+	}
+
+	// Keywords for string-encoded data
+	if (s.ContentEncoding != nil || s.ContentMediaType != nil || s.ContentSchema != nil) && s.hasVocabulary(VocabularyContent) {
+		add(func(s *Schema, instance interface{}, result *EvaluationResult, evaluatedProps map[string]bool, evaluatedItems map[int]bool, dynamicScope *DynamicScope) {
+			contentResult, contentError := evaluateContent(s, instance, evaluatedProps, evaluatedItems, dynamicScope)
+			if contentError != nil {
+				recordOne(result, contentResult, contentError)
+			}
+			// The content keywords only annotate strings, whose encoded data they describe.
+			if _, isString := instance.(string); isString && dynamicScope.annotates() {
+				if s.ContentEncoding != nil {
+					result.AddAnnotation("contentEncoding", *s.ContentEncoding)
+				}
+				if s.ContentMediaType != nil {
+					result.AddAnnotation("contentMediaType", *s.ContentMediaType)
+				}
+			}
+		})
+	}
+
+	return plan
+}
+
+// record adds the subschema results and the errors of a keyword to result.
+func record(result *EvaluationResult, details []*EvaluationResult, errs ...*EvaluationError) {
+	for _, detail := range details {
+		result.AddDetail(detail)
+	}
+	for _, err := range errs {
+		if err != nil {
+			result.AddError(err)
+		}
+	}
+}
+
+// recordOne adds the result of the single subschema of a keyword, if it was evaluated, and the error of
+// the keyword to result.
+func recordOne(result *EvaluationResult, detail *EvaluationResult, err *EvaluationError) {
+	if detail != nil {
+		result.AddDetail(detail)
+	}
+	record(result, nil, err)
+}
+
+// recordFirst adds the errors of a group of keywords to result, only keeping the first one in
+// fail-fast mode.
+func recordFirst(result *EvaluationResult, errs []*EvaluationError, dynamicScope *DynamicScope) {
+	for _, err := range errs {
+		if dynamicScope.stops(!result.Valid) {
+			break
+		}
+		result.AddError(err)
+	}
+}
+
+// evaluateRef evaluates the instance against the schema referenced by the $ref keyword. The tf://
+// references are resolved per instance from its "@schema" member, so the resolved schema is kept local
+// to this evaluation instead of being stored on s.
+func evaluateRef(s *Schema, instance interface{}, result *EvaluationResult, evaluatedProps map[string]bool, evaluatedItems map[int]bool, dynamicScope *DynamicScope) {
+	resolvedRef := s.ResolvedRef
+	if strings.HasPrefix(s.Ref, "tf://") {
+		resolvedRef = nil
+
+		value, ok := instance.(map[string]interface{})
+		if !ok {
+			result.AddError(
+				NewEvaluationError("@schema", "type_not_found", "Cant find the reference type schema"),
+			)
+		} else if schemaRef, ok := value["@schema"].(string); !ok {
+			result.AddError(
+				NewEvaluationError("@schema", "type_not_found", "Cant find the reference type schema"),
+			)
+		} else {
+			var err error
+			resolvedRef, err = s.resolveRef(dynamicScope.Context(), schemaRef)
+			if err != nil {
+				result.AddError(
+					NewEvaluationError("@schema", "schema_cant_reach", "Cant reach the reference @schema"),
+				)
+			}
+		}
+	}
+
+	if resolvedRef == nil || dynamicScope.stops(!result.Valid) {
+		return
+	}
+
+	refResult, props, items := resolvedRef.evaluateIn(instance, "/$ref", dynamicScope)
+	result.AddDetail(refResult)
+	if !refResult.IsValid() {
+		result.AddError(
+			NewEvaluationError("$ref", "ref_mismatch", "Value does not match the reference schema"),
+		)
+	}
+
+	mergeStringMaps(evaluatedProps, props)
+	mergeIntMaps(evaluatedItems, items)
+}
+
+// evaluateDynamicRef evaluates the instance against the schema referenced by the $dynamicRef keyword,
+// which is the outermost schema of the dynamic scope declaring its dynamic anchor, if any.
+func evaluateDynamicRef(s *Schema, instance interface{}, result *EvaluationResult, evaluatedProps map[string]bool, evaluatedItems map[int]bool, dynamicScope *DynamicScope) {
+	if s.ResolvedDynamicRef == nil {
+		return
+	}
+
+	anchorSchema := s.ResolvedDynamicRef
+	_, anchor := splitRef(s.DynamicRef)
+	if !isJSONPointer(anchor) {
+		dynamicAnchor := s.ResolvedDynamicRef.DynamicAnchor
+		if dynamicAnchor != "" {
+			if schema := dynamicScope.LookupDynamicAnchor(dynamicAnchor); schema != nil {
+				anchorSchema = schema
+			}
+		}
+	}
+
+	dynamicRefResult, props, items := anchorSchema.evaluateIn(instance, "/$dynamicRef", dynamicScope)
+	result.AddDetail(dynamicRefResult)
+	if !dynamicRefResult.IsValid() {
+		result.AddError(
+			NewEvaluationError("$dynamicRef", "dynamic_ref_mismatch", "Value does not match the dynamic reference schema"),
+		)
+	}
+
+	mergeStringMaps(evaluatedProps, props)
+	mergeIntMaps(evaluatedItems, items)
+}
+
+// pathNode is the last segment of a JSON Pointer, linked to the node of the pointer it extends. The
+// pointer is only built when it is requested, then kept, so that the results sharing a location share
+// its string, and the locations of the results dropped in fail-fast mode are never built.
+type pathNode struct {
+	parent  *pathNode
+	segment string // Unescaped segment of an instance location, or escaped keyword path of an evaluation path.
+	escape  bool   // Whether segment needs escaping, that is whether it extends an instance location.
+	pointer string
+	built   bool
+}
+
+// String returns the JSON Pointer ending with n, or an empty string for a nil node.
+func (n *pathNode) String() string {
+	if n == nil {
+		return ""
+	}
+	if !n.built {
+		if n.escape {
+			n.pointer = n.parent.String() + "/" + escapeJSONPointerSegment(n.segment)
+		} else {
+			n.pointer = n.parent.String() + n.segment
+		}
+		n.built = true
+	}
+	return n.pointer
+}
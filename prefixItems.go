@@ -1,7 +1,6 @@
 package jsonschema
 
 import (
-	"fmt"
 	"strconv"
 	"strings"
 )
@@ -15,7 +14,7 @@ import (
 //   - Omitting this keyword implies an empty array behavior, meaning no validation is enforced on the array items.
 //
 // If validation fails, it returns a EvaluationError detailing the index and discrepancy.
-func evaluatePrefixItems(schema *Schema, array []interface{}, evaluatedProps map[string]bool, evaluatedItems map[int]bool, dynamicScope *DynamicScope) ([]*EvaluationResult, *EvaluationError) {
+func evaluatePrefixItems(schema *Schema, array []interface{}, result *EvaluationResult, evaluatedProps map[string]bool, evaluatedItems map[int]bool, dynamicScope *DynamicScope) ([]*EvaluationResult, *EvaluationError) {
 	if schema.PrefixItems == nil || len(schema.PrefixItems) == 0 {
 		return nil, nil // If no prefixItems are defined, there is nothing to validate against.
 	}
@@ -24,25 +23,36 @@ func evaluatePrefixItems(schema *Schema, array []interface{}, evaluatedProps map
 	results := []*EvaluationResult{}
 
 	for i, itemSchema := range schema.PrefixItems {
+		if dynamicScope.stops(len(invalid_indexs) > 0) {
+			break
+		}
 		if i >= len(array) {
 			break // Stop validation if there are more schemas than array items.
 		}
 
-		result, _, _ := itemSchema.evaluate(array[i], dynamicScope)
-		if result != nil {
-			results = append(results, result.SetEvaluationPath(fmt.Sprintf("/prefixItems/%d", i)).
-				SetSchemaLocation(schema.GetSchemaLocation(fmt.Sprintf("/prefixItems/%d", i))).
-				SetInstanceLocation(fmt.Sprintf("/%d", i)),
-			)
+		itemResult, _, _ := itemSchema.evaluateAt(array[i], "/prefixItems/"+strconv.Itoa(i), strconv.Itoa(i), dynamicScope)
+		if itemResult != nil {
+			results = append(results, itemResult)
 
-			if result.IsValid() {
-				evaluatedItems[i] = true // Mark the item as evaluated if it passes schema validation.
+			if itemResult.IsValid() {
+				if evaluatedItems != nil {
+					evaluatedItems[i] = true // Mark the item as evaluated if it passes schema validation.
+				}
 			} else {
 				invalid_indexs = append(invalid_indexs, strconv.Itoa(i))
 			}
 		}
 	}
 
+	// The annotation is the largest index the subschemas applied to, or true if they applied to every item.
+	if applied := min(len(schema.PrefixItems), len(array)); applied > 0 && dynamicScope.annotates() {
+		if applied == len(array) {
+			result.AddAnnotation("prefixItems", true)
+		} else {
+			result.AddAnnotation("prefixItems", applied-1)
+		}
+	}
+
 	if len(invalid_indexs) == 1 {
 		return results, NewEvaluationError("prefixItems", "prefix_item_mismatch", "Item at index {index} does not match the prefixItems schema", map[string]interface{}{
 			"index": invalid_indexs[0],
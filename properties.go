@@ -15,48 +15,50 @@ import (
 // If a property does not conform, it returns a EvaluationError detailing the issue with that property.
 //
 // Reference: https://json-schema.org/draft/2020-12/json-schema-core#name-properties
-func evaluateProperties(schema *Schema, object map[string]interface{}, evaluatedProps map[string]bool, evaluatedItems map[int]bool, dynamicScope *DynamicScope) ([]*EvaluationResult, *EvaluationError) {
+func evaluateProperties(schema *Schema, object map[string]interface{}, result *EvaluationResult, evaluatedProps map[string]bool, evaluatedItems map[int]bool, dynamicScope *DynamicScope) ([]*EvaluationResult, *EvaluationError) {
 	if schema.Properties == nil {
 		return nil, nil // No properties defined, nothing to do.
 	}
 
 	invalid_properties := []string{}
 	results := []*EvaluationResult{}
+	matched := []string{}
 
 	for propName, propSchema := range *schema.Properties {
-		evaluatedProps[propName] = true
+		if dynamicScope.stops(len(invalid_properties) > 0) {
+			break
+		}
+		if evaluatedProps != nil {
+			evaluatedProps[propName] = true
+		}
 		propValue, exists := object[propName]
 
 		if exists {
-			result, _, _ := propSchema.evaluate(propValue, dynamicScope)
-			if result != nil {
-				result.SetEvaluationPath(fmt.Sprintf("/properties/%s", propName)).
-					SetSchemaLocation(schema.GetSchemaLocation(fmt.Sprintf("/properties/%s", propName))).
-					SetInstanceLocation(fmt.Sprintf("/%s", propName))
-
-				results = append(results, result)
+			if dynamicScope.annotates() {
+				matched = append(matched, propName)
+			}
+			propResult, _, _ := propSchema.evaluateAt(propValue, "/properties/"+escapeJSONPointerSegment(propName), propName, dynamicScope)
+			if propResult != nil {
+				results = append(results, propResult)
 
-				if !result.IsValid() {
+				if !propResult.IsValid() {
 					invalid_properties = append(invalid_properties, propName)
 				}
 			}
-		} else if isRequired(schema, propName) && !defaultIsSpecified(propSchema) {
+		} else if isRequired(schema, propName) && !defaultIsSpecified(propSchema) && !dynamicScope.direction.forbids(propSchema) {
 			// Handle properties that are expected but not provided
-			result, _, _ := propSchema.evaluate(nil, dynamicScope)
-
-			if result != nil {
-				result.SetEvaluationPath(fmt.Sprintf("/properties/%s", propName)).
-					SetSchemaLocation(schema.GetSchemaLocation(fmt.Sprintf("/properties/%s", propName))).
-					SetInstanceLocation(fmt.Sprintf("/%s", propName))
+			propResult, _, _ := propSchema.evaluateAt(nil, "/properties/"+escapeJSONPointerSegment(propName), propName, dynamicScope)
 
-				results = append(results, result)
+			if propResult != nil {
+				results = append(results, propResult)
 
-				if !result.IsValid() {
+				if !propResult.IsValid() {
 					invalid_properties = append(invalid_properties, propName)
 				}
 			}
 		}
 	}
+	annotateNames(result, "properties", matched)
 
 	if len(invalid_properties) == 1 {
 		return results, NewEvaluationError("properties", "property_mismatch", "Property {property} does not match the schema", map[string]interface{}{
@@ -27,12 +27,12 @@ func evaluatePropertyNames(schema *Schema, object map[string]interface{}, evalua
 
 	if schema.PropertyNames != nil {
 		for propName := range object {
-			result, _, _ := schema.PropertyNames.evaluate(propName, dynamicScope)
+			if dynamicScope.stops(len(invalid_properties) > 0) {
+				break
+			}
+			result, _, _ := schema.PropertyNames.evaluateAt(propName, "/propertyNames", propName, dynamicScope)
 
 			if result != nil {
-				result.SetEvaluationPath(fmt.Sprintf("/propertyNames/%s", propName)).
-					SetSchemaLocation(schema.GetSchemaLocation(fmt.Sprintf("/propertyNames/%s", propName))).
-					SetInstanceLocation(fmt.Sprintf("/%s", propName))
 			}
 
 			results = append(results, result)
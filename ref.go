@@ -1,15 +1,14 @@
 package jsonschema
 
 import (
-	"errors"
+	"context"
 	"fmt"
 	"net/url"
-	"strconv"
 	"strings"
 )
 
 // resolveRef resolves a reference to another schema, either locally or globally, supporting both $ref and $dynamicRef.
-func (s *Schema) resolveRef(ref string) (*Schema, error) {
+func (s *Schema) resolveRef(ctx context.Context, ref string) (*Schema, error) {
 	if ref == "#" {
 		return s.getRootSchema(), nil
 	}
@@ -24,7 +23,7 @@ func (s *Schema) resolveRef(ref string) (*Schema, error) {
 	}
 
 	// Handle full URL references
-	return s.resolveRefWithFullURL(ref)
+	return s.resolveRefWithFullURL(ctx, ref)
 }
 
 func (s *Schema) resolveAnchor(anchorName string) (*Schema, error) {
@@ -51,185 +50,115 @@ func (s *Schema) resolveAnchor(anchorName string) (*Schema, error) {
 }
 
 // resolveRefWithFullURL resolves a full URL reference to another schema.
-func (s *Schema) resolveRefWithFullURL(ref string) (*Schema, error) {
+func (s *Schema) resolveRefWithFullURL(ctx context.Context, ref string) (*Schema, error) {
 	root := s.getRootSchema()
 	if resolved, err := root.getSchema(ref); err == nil {
 		return resolved, nil
 	}
 
 	// If not found in the current schema or its parents, look for the reference in the compiler
-	if resolved, err := s.compiler.GetSchema(ref); err != nil {
-		return nil, errors.New(fmt.Sprintf("%s: %s", ErrFailedToResolveGlobalReference, ref))
-	} else {
-		return resolved, nil
+	resolved, err := s.compiler.GetSchemaContext(ctx, ref)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s: %w", ErrFailedToResolveGlobalReference, ref, err)
 	}
+	return resolved, nil
 }
 
 // resolveJSONPointer resolves a JSON Pointer within the schema based on JSON Schema structure.
+// Every keyword that applies subschemas can appear in the pointer.
 func (s *Schema) resolveJSONPointer(pointer string) (*Schema, error) {
 	if pointer == "/" {
 		return s, nil
 	}
 
 	segments := strings.Split(strings.TrimPrefix(pointer, "/"), "/")
-	currentSchema := s
-	previousSegment := ""
-
 	for i, segment := range segments {
 		decodedSegment, err := url.PathUnescape(strings.ReplaceAll(strings.ReplaceAll(segment, "~1", "/"), "~0", "~"))
 		if err != nil {
 			return nil, ErrFailedToDecodeSegmentWithJSONPointer
 		}
+		segments[i] = decodedSegment
+	}
 
-		nextSchema, found := findSchemaInSegment(currentSchema, decodedSegment, previousSegment)
-		if found {
-			currentSchema = nextSchema
-			previousSegment = decodedSegment // Update the context for the next iteration
-			continue
-		}
-
-		if !found && i == len(segments)-1 {
-			// If no schema is found and it's the last segment, throw error
+	currentSchema := s
+	for len(segments) > 0 {
+		nextSchema, consumed := currentSchema.subschemaAt(segments)
+		if nextSchema == nil {
 			return nil, ErrSegmentNotFoundForJSONPointer
 		}
-
-		previousSegment = decodedSegment // Update the context for the next iteration
+		currentSchema = nextSchema
+		segments = segments[consumed:]
 	}
 
 	return currentSchema, nil
 }
 
-// Helper function to find a schema within a given segment
-func findSchemaInSegment(currentSchema *Schema, segment string, previousSegment string) (*Schema, bool) {
-	switch previousSegment {
-	case "properties":
-		if currentSchema.Properties != nil {
-			if schema, exists := (*currentSchema.Properties)[segment]; exists {
-				return schema, true
-			}
-		}
-	case "prefixItems":
-		index, err := strconv.Atoi(segment)
-
-		if err == nil && currentSchema.PrefixItems != nil && index < len(currentSchema.PrefixItems) {
-			return currentSchema.PrefixItems[index], true
-		}
-	case "$defs":
-		if defSchema, exists := currentSchema.Defs[segment]; exists {
-			return defSchema, true
-		}
-	case "items":
-		if currentSchema.Items != nil {
-			return currentSchema.Items, true
-		}
-	}
-	return nil, false
-}
-
-func (s *Schema) resolveReferences() (err error) {
-	// Resolve the root reference if this schema itself is a reference
+// resolveReferences resolves the $ref and $dynamicRef keywords of s and of all its subschemas.
+// Resolution continues past unresolvable references, and a ReferenceError is returned for each of them.
+func (s *Schema) resolveReferences(ctx context.Context) (errs []*ReferenceError) {
 	if s.Ref != "" {
-		s.ResolvedRef, err = s.resolveRef(s.Ref) // Resolve against root schema
-		if err != nil {
-			return
-		}
+		s.ResolvedRef = s.resolveKeywordRef(ctx, "$ref", s.Ref, &errs)
 	}
-
 	if s.DynamicRef != "" {
-		s.ResolvedDynamicRef, err = s.resolveRef(s.DynamicRef) // Resolve dynamic references against root schema
-		if err != nil {
-			return
-		}
+		s.ResolvedDynamicRef = s.resolveKeywordRef(ctx, "$dynamicRef", s.DynamicRef, &errs)
 	}
 
-	// Recursively resolve references within definitions
-	if s.Defs != nil {
-		for _, defSchema := range s.Defs {
-			err = defSchema.resolveReferences()
-			if err != nil {
-				return
-			}
-		}
-	}
-
-	// Recursively resolve references in properties
-	if s.Properties != nil {
-		for _, schema := range *s.Properties {
-			if schema != nil {
-				err = schema.resolveReferences()
-				if err != nil {
-					return
-				}
-			}
-		}
+	for _, sub := range s.subschemas() {
+		errs = append(errs, sub.schema.resolveReferences(ctx)...)
 	}
+	return
+}
 
-	// Additional fields that can have subschemas
-	err = resolveSubschemaList(s.AllOf)
-	if err != nil {
-		return
-	}
-	err = resolveSubschemaList(s.AnyOf)
-	if err != nil {
-		return
+// resolveKeywordRef resolves the reference held by the given keyword of s. When the reference
+// cannot be resolved, a ReferenceError is appended to errs and nil is returned.
+func (s *Schema) resolveKeywordRef(ctx context.Context, keyword string, ref string, errs *[]*ReferenceError) *Schema {
+	resolved, err := s.resolveRef(ctx, ref)
+	if err == nil && resolved == nil {
+		err = ErrFailedToResolveAnchor
 	}
-	err = resolveSubschemaList(s.OneOf)
 	if err != nil {
-		return
-	}
-	if s.Not != nil {
-		err = s.Not.resolveReferences()
-		if err != nil {
-			return
-		}
-	}
-	if s.Items != nil {
-		err = s.Items.resolveReferences()
-		if err != nil {
-			return
-		}
-	}
-	if s.PrefixItems != nil {
-		for _, schema := range s.PrefixItems {
-			err = schema.resolveReferences()
-			if err != nil {
-				return
-			}
-		}
-	}
+		// The tf:// references are resolved per instance when evaluating, so they are not reported here.
+		if !strings.HasPrefix(ref, "tf://") {
+			*errs = append(*errs, &ReferenceError{
+				Keyword:  keyword,
+				Ref:      ref,
+				URI:      s.refURI(ref),
+				Location: s.location + "/" + keyword,
+				Err:      err,
+			})
+		}
+		return nil
+	}
+	return resolved
+}
 
-	if s.AdditionalProperties != nil {
-		err = s.AdditionalProperties.resolveReferences()
-		if err != nil {
-			return
-		}
-	}
-	if s.Contains != nil {
-		err = s.Contains.resolveReferences()
-		if err != nil {
-			return
-		}
+// refURI returns the URI a reference is resolved against: fragments are relative to the schema
+// resource and relative references to its base URI.
+func (s *Schema) refURI(ref string) string {
+	if strings.HasPrefix(ref, "#") {
+		return s.GetSchemaURI() + ref
 	}
-	if s.PatternProperties != nil {
-		for _, schema := range *s.PatternProperties {
-			err = schema.resolveReferences()
-			if err != nil {
-				return
-			}
-		}
+	if !isAbsoluteURI(ref) && s.baseURI != "" {
+		return resolveRelativeURI(s.baseURI, ref)
 	}
-	return
+	return ref
 }
 
-// Helper function to resolve references in a list of schemas
-func resolveSubschemaList(schemas []*Schema) (err error) {
-	for _, schema := range schemas {
-		if schema != nil {
-			err = schema.resolveReferences()
-			if err != nil {
-				return
-			}
-		}
-	}
-	return
+// ReferenceError describes a $ref or $dynamicRef that could not be resolved when compiling a schema.
+type ReferenceError struct {
+	Keyword  string // Either "$ref" or "$dynamicRef".
+	Ref      string // The reference as written in the schema.
+	URI      string // The URI the reference was resolved to.
+	Location string // JSON Pointer of the keyword within its schema document, such as "/properties/name/$ref".
+	Err      error  // The reason the reference could not be resolved.
+}
+
+// Error returns a description of the unresolved reference and its cause.
+func (e *ReferenceError) Error() string {
+	return fmt.Sprintf("unresolved %s %q at %q (tried %s): %v", e.Keyword, e.Ref, e.Location, e.URI, e.Err)
+}
+
+// Unwrap returns ErrFailedToResolveReference together with the underlying cause, so both can be matched with errors.Is.
+func (e *ReferenceError) Unwrap() []error {
+	return []error{ErrFailedToResolveReference, e.Err}
 }
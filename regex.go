@@ -0,0 +1,163 @@
+package jsonschema
+
+import (
+	"container/list"
+	"fmt"
+	"regexp"
+	"sync"
+)
+
+// defaultRegexCacheSize is the number of compiled regular expressions a Compiler keeps by default.
+const defaultRegexCacheSize = 1024
+
+// RegexEngine compiles the regular expressions used by the pattern and patternProperties keywords
+// and by the regex format. The JSON Schema specification expects the ECMA-262 dialect; the default
+// engine uses Go's RE2 syntax, and ECMAScriptRegexEngine can be selected with Compiler.SetRegexEngine.
+// Implementations must be safe for concurrent use.
+type RegexEngine interface {
+	Compile(pattern string) (Regexp, error)
+}
+
+// Regexp is a compiled regular expression. Implementations must be safe for concurrent use.
+type Regexp interface {
+	MatchString(s string) bool
+}
+
+// RE2RegexEngine compiles regular expressions with Go's regexp package. It is the default engine.
+type RE2RegexEngine struct{}
+
+// Compile compiles pattern using the RE2 syntax.
+func (RE2RegexEngine) Compile(pattern string) (Regexp, error) {
+	compiled, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+	return compiled, nil
+}
+
+// regexCache is a bounded, concurrency-safe cache of compiled regular expressions shared by all
+// schemas of a Compiler. When the cache is full, the least recently used expression is evicted.
+type regexCache struct {
+	mu       sync.Mutex
+	engine   RegexEngine
+	capacity int
+	entries  map[string]*list.Element
+	order    *list.List
+}
+
+// regexCacheEntry is a single compiled regular expression stored in a regexCache.
+type regexCacheEntry struct {
+	pattern string
+	regexp  Regexp
+}
+
+// newRegexCache creates a regexCache holding at most capacity expressions compiled by engine.
+func newRegexCache(engine RegexEngine, capacity int) *regexCache {
+	return &regexCache{
+		engine:   engine,
+		capacity: capacity,
+		entries:  make(map[string]*list.Element),
+		order:    list.New(),
+	}
+}
+
+// compile returns the compiled regular expression for pattern, compiling and caching it if needed.
+func (rc *regexCache) compile(pattern string) (Regexp, error) {
+	rc.mu.Lock()
+	if element, ok := rc.entries[pattern]; ok {
+		rc.order.MoveToFront(element)
+		rc.mu.Unlock()
+		return element.Value.(*regexCacheEntry).regexp, nil
+	}
+	rc.mu.Unlock()
+
+	compiled, err := rc.engine.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	if element, ok := rc.entries[pattern]; ok {
+		rc.order.MoveToFront(element)
+		return element.Value.(*regexCacheEntry).regexp, nil
+	}
+
+	rc.entries[pattern] = rc.order.PushFront(&regexCacheEntry{pattern: pattern, regexp: compiled})
+	for rc.capacity > 0 && rc.order.Len() > rc.capacity {
+		oldest := rc.order.Back()
+		rc.order.Remove(oldest)
+		delete(rc.entries, oldest.Value.(*regexCacheEntry).pattern)
+	}
+
+	return compiled, nil
+}
+
+// len returns the number of cached regular expressions.
+func (rc *regexCache) len() int {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	return rc.order.Len()
+}
+
+// compileRegexps compiles the regular expressions of the pattern and patternProperties keywords,
+// so that evaluation only reads the compiled expressions. An invalid expression is reported
+// together with the location of the keyword in the schema document.
+func (s *Schema) compileRegexps(compiler *Compiler) error {
+	if s.Pattern != nil {
+		compiled, err := compiler.compileRegexp(*s.Pattern)
+		if err != nil {
+			return s.newRegexpError(*s.Pattern, s.location+"/pattern", err)
+		}
+		s.compiledPattern = compiled
+	}
+
+	if s.PatternProperties != nil {
+		s.compiledPatterns = make(map[string]Regexp, len(*s.PatternProperties))
+		for pattern := range *s.PatternProperties {
+			compiled, err := compiler.compileRegexp(pattern)
+			if err != nil {
+				return s.newRegexpError(pattern, s.location+"/patternProperties/"+escapeJSONPointerSegment(pattern), err)
+			}
+			s.compiledPatterns[pattern] = compiled
+		}
+	}
+
+	return nil
+}
+
+// newRegexpError describes an invalid regular expression found at the given location of the schema document.
+func (s *Schema) newRegexpError(pattern string, location string, err error) error {
+	return fmt.Errorf("%w %q at %s: %w", ErrInvalidRegexPattern, pattern, s.getRootSchema().GetSchemaLocation(location), err)
+}
+
+// compileRegexp compiles a regular expression through the compiler's shared cache.
+func (c *Compiler) compileRegexp(pattern string) (Regexp, error) {
+	if c == nil {
+		return RE2RegexEngine{}.Compile(pattern)
+	}
+
+	c.mu.RLock()
+	regexps := c.regexps
+	c.mu.RUnlock()
+
+	return regexps.compile(pattern)
+}
+
+// isRegex tells whether the given string is a valid regular expression for the compiler's engine.
+// Unlike compileRegexp, it bypasses the cache, as the checked strings come from instances.
+func (c *Compiler) isRegex(v interface{}) bool {
+	pattern, ok := v.(string)
+	if !ok {
+		return true
+	}
+
+	c.mu.RLock()
+	engine := c.regexps.engine
+	c.mu.RUnlock()
+
+	_, err := engine.Compile(pattern)
+	return err == nil
+}
@@ -0,0 +1,140 @@
+package jsonschema
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/test-go/testify/assert"
+)
+
+func TestCompileInvalidPattern(t *testing.T) {
+	testCases := []struct {
+		name       string
+		schemaJSON string
+		location   string
+	}{
+		{
+			name:       "pattern",
+			schemaJSON: `{"$id": "http://example.com/pattern", "properties": {"name": {"pattern": "^(foo"}}}`,
+			location:   "http://example.com/pattern#/properties/name/pattern",
+		},
+		{
+			name:       "patternProperties",
+			schemaJSON: `{"$defs": {"tags": {"patternProperties": {"a/[": true}}}}`,
+			location:   "#/$defs/tags/patternProperties/a~1[",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := NewCompiler().Compile([]byte(tc.schemaJSON))
+			assert.True(t, errors.Is(err, ErrInvalidRegexPattern), "Expected ErrInvalidRegexPattern, got %v", err)
+			assert.Contains(t, err.Error(), tc.location)
+		})
+	}
+}
+
+func TestCompileSharesRegexps(t *testing.T) {
+	compiler := NewCompiler()
+	schema, err := compiler.Compile([]byte(`{
+		"properties": {
+			"a": {"pattern": "^[a-z]+$"},
+			"b": {"pattern": "^[a-z]+$"}
+		},
+		"patternProperties": {"^[a-z]+$": {"type": "string"}}
+	}`))
+	assert.NoError(t, err)
+
+	a := (*schema.Properties)["a"]
+	b := (*schema.Properties)["b"]
+	assert.True(t, a.compiledPattern == b.compiledPattern, "Expected identical patterns to share one compiled expression")
+	assert.True(t, a.compiledPattern == schema.compiledPatterns["^[a-z]+$"])
+	assert.Equal(t, 1, compiler.regexps.len())
+
+	assert.True(t, schema.Validate(map[string]interface{}{"a": "abc", "x": "y"}).IsValid())
+	assert.False(t, schema.Validate(map[string]interface{}{"a": "ABC"}).IsValid())
+	assert.False(t, schema.Validate(map[string]interface{}{"x": 1}).IsValid())
+}
+
+func TestRegexCacheIsBounded(t *testing.T) {
+	cache := newRegexCache(RE2RegexEngine{}, 2)
+
+	first, err := cache.compile("^a$")
+	assert.NoError(t, err)
+	_, err = cache.compile("^b$")
+	assert.NoError(t, err)
+
+	again, err := cache.compile("^a$")
+	assert.NoError(t, err)
+	assert.True(t, first == again, "Expected cached expression to be reused")
+
+	_, err = cache.compile("^c$")
+	assert.NoError(t, err)
+	assert.Equal(t, 2, cache.len())
+
+	_, ok := cache.entries["^b$"]
+	assert.False(t, ok, "Expected the least recently used expression to be evicted")
+
+	_, err = cache.compile("(")
+	assert.Error(t, err)
+	_, ok = cache.entries["("]
+	assert.False(t, ok, "Expected invalid expressions not to be cached")
+}
+
+func TestECMAScriptRegexEngine(t *testing.T) {
+	engine := ECMAScriptRegexEngine{}
+
+	testCases := []struct {
+		pattern string
+		input   string
+		match   bool
+	}{
+		{pattern: `^(?=.*\d)[a-z\d]+$`, input: "abc1", match: true},
+		{pattern: `^(?=.*\d)[a-z\d]+$`, input: "abc", match: false},
+		{pattern: `^(a+)-\1$`, input: "aa-aa", match: true},
+		{pattern: `^(a+)-\1$`, input: "aa-a", match: false},
+		{pattern: `^\d$`, input: "৪", match: false},
+		{pattern: `^\w$`, input: "é", match: false},
+		{pattern: `^abc$`, input: "abc\n", match: false},
+		{pattern: `^\p{Lu}\p{Lowercase_Letter}+$`, input: "École", match: true},
+		{pattern: `^\p{digit}+$`, input: "৪২", match: true},
+		{pattern: `^\p{gc=Cased_Letter}$`, input: "a", match: true},
+		{pattern: `^\p{Script=Greek}+$`, input: "αβγ", match: true},
+		{pattern: `^\P{L}+$`, input: "123", match: true},
+		{pattern: `^\\a$`, input: `\a`, match: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.pattern+" "+tc.input, func(t *testing.T) {
+			compiled, err := engine.Compile(tc.pattern)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.match, compiled.MatchString(tc.input))
+		})
+	}
+
+	for _, pattern := range []string{`\a`, `[\e]`, `\p{Letter`, `\p{gc=Unknown}`, `(`} {
+		_, err := engine.Compile(pattern)
+		assert.Error(t, err, "Expected %q to be rejected", pattern)
+	}
+}
+
+func TestSetRegexEngine(t *testing.T) {
+	schemaJSON := []byte(`{
+		"properties": {
+			"password": {"pattern": "^(?=.*[0-9]).{8,}$"},
+			"expr": {"format": "regex"}
+		}
+	}`)
+
+	_, err := NewCompiler().Compile(schemaJSON)
+	assert.True(t, errors.Is(err, ErrInvalidRegexPattern), "Expected RE2 to reject lookaheads, got %v", err)
+
+	compiler := NewCompiler().SetRegexEngine(ECMAScriptRegexEngine{}).SetAssertFormat(true)
+	schema, err := compiler.Compile(schemaJSON)
+	assert.NoError(t, err)
+
+	assert.True(t, schema.Validate(map[string]interface{}{"password": "secret123"}).IsValid())
+	assert.False(t, schema.Validate(map[string]interface{}{"password": "secretpassword"}).IsValid())
+	assert.True(t, schema.Validate(map[string]interface{}{"expr": "(?<=a)b"}).IsValid())
+	assert.False(t, schema.Validate(map[string]interface{}{"expr": `\a`}).IsValid())
+}
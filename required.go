@@ -13,9 +13,10 @@ import (
 //
 // This method ensures that all properties listed as required are present in the data instance.
 // If a required property is missing, it returns a EvaluationError detailing the missing properties.
+// A required property the direction of the validation forbids may be missing, see WithDirection.
 //
 // Reference: https://json-schema.org/draft/2020-12/json-schema-validation#name-required
-func evaluateRequired(schema *Schema, object map[string]interface{}) *EvaluationError {
+func evaluateRequired(schema *Schema, object map[string]interface{}, direction Direction) *EvaluationError {
 	if schema.Required == nil {
 		// No required properties defined, nothing to do.
 		return nil
@@ -24,7 +25,7 @@ func evaluateRequired(schema *Schema, object map[string]interface{}) *Evaluation
 	// Proceed with checking for required properties only if it is indeed an object.
 	var missingProps []string
 	for _, propName := range schema.Required {
-		if _, exists := object[propName]; !exists {
+		if _, exists := object[propName]; !exists && !direction.exempts(schema, propName) {
 			missingProps = append(missingProps, propName)
 		}
 	}
@@ -2,6 +2,8 @@ package jsonschema
 
 import "github.com/kaptinlin/go-i18n"
 
+// EvaluationError is the error of a keyword an instance does not conform to. Its code identifies the
+// kind of failure and names the localized message, whose placeholders are filled from its params.
 type EvaluationError struct {
 	keyword string                 `json:"-"`
 	code    string                 `json:"-"`
@@ -30,6 +32,33 @@ func (e *EvaluationError) Error() string {
 	return replace(e.message, e.params)
 }
 
+// Keyword returns the keyword that failed, such as "minimum".
+func (e *EvaluationError) Keyword() string {
+	return e.keyword
+}
+
+// Code returns the code identifying the failure, such as "value_below_minimum".
+func (e *EvaluationError) Code() string {
+	return e.code
+}
+
+// Params returns the values the message of the error is formatted with.
+func (e *EvaluationError) Params() map[string]interface{} {
+	return e.params
+}
+
+// Is reports whether target is an *EvaluationError with the same code and, if target has one, the same
+// keyword, so that failures can be matched with errors.Is:
+//
+//	errors.Is(result.Err(), jsonschema.NewEvaluationError("", "type_mismatch", ""))
+func (e *EvaluationError) Is(target error) bool {
+	t, ok := target.(*EvaluationError)
+	if !ok {
+		return false
+	}
+	return t.code == e.code && (t.keyword == "" || t.keyword == e.keyword)
+}
+
 func (e *EvaluationError) Localize(localizer *i18n.Localizer) string {
 	if localizer != nil {
 		return localizer.Get(e.code, i18n.Vars(e.params))
@@ -50,19 +79,25 @@ type List struct {
 	Annotations      map[string]interface{} `json:"annotations,omitempty"`
 	Errors           map[string]string      `json:"errors,omitempty"`
 	Details          []List                 `json:"details,omitempty"`
-	XTFFacets        []string               `json:"x-tf-facets,omitempty"`
+	// Deprecated: XTFFacets lists the facets of the schema of the result and of the schemas above it,
+	// which the x-tf-facets annotations also report.
+	XTFFacets []string `json:"x-tf-facets,omitempty"`
 }
 
 type EvaluationResult struct {
 	schema           *Schema                     `json:"-"`
 	Valid            bool                        `json:"valid"`
-	EvaluationPath   string                      `json:"evaluationPath"`
-	SchemaLocation   string                      `json:"schemaLocation"`
-	InstanceLocation string                      `json:"instanceLocation"`
+	EvaluationPath   string                      `json:"evaluationPath"`   // Keywords followed from the root schema, through references
+	SchemaLocation   string                      `json:"schemaLocation"`   // URI of the schema, see GetSchemaLocation
+	InstanceLocation string                      `json:"instanceLocation"` // JSON Pointer of the value, from the root of the instance
 	Annotations      map[string]interface{}      `json:"annotations,omitempty"`
-	Errors           map[string]*EvaluationError `json:"errors,omitempty"` // Store error messages here
+	Errors           map[string]*EvaluationError `json:"errors,omitempty"` // Last error of each keyword, see ErrorList
 	Details          []*EvaluationResult         `json:"details,omitempty"`
-	XTFFacets        []string                    `json:"x-tf-facets,omitempty"`
+	XTFFacets        []string                    `json:"x-tf-facets,omitempty"` // Deprecated: use the x-tf-facets annotation.
+	errorList        []*EvaluationError          // Errors in the order they were added
+	evaluationPath   *pathNode                   // Evaluation path, until set by locate
+	instanceLocation *pathNode                   // Instance location, until set by locate
+	pending          bool                        // Whether the locations of the result and of its details are still to be set
 }
 
 func NewEvaluationResult(schema *Schema) *EvaluationResult {
@@ -114,14 +149,17 @@ func (e *EvaluationResult) AddError(err *EvaluationError) *EvaluationResult {
 	}
 
 	e.Errors[err.keyword] = err
+	e.errorList = append(e.errorList, err)
 	return e
 }
 
-func (e *EvaluationResult) AddDetail(detail *EvaluationResult) *EvaluationResult {
-	if e.Details == nil {
-		e.Details = make([]*EvaluationResult, 0)
-	}
+// ErrorList returns the errors of the result itself, without those of its details, in the order they
+// were added. Unlike Errors, it keeps every error of a keyword that failed more than once.
+func (e *EvaluationResult) ErrorList() []*EvaluationError {
+	return e.errorList
+}
 
+func (e *EvaluationResult) AddDetail(detail *EvaluationResult) *EvaluationResult {
 	e.Details = append(e.Details, detail)
 	return e
 }
@@ -135,36 +173,49 @@ func (e *EvaluationResult) AddAnnotation(keyword string, annotation interface{})
 	return e
 }
 
+// CollectAnnotations adds the annotations of the meta-data keywords of the schema to the result. The
+// annotations map is only allocated if the schema has one.
 func (e *EvaluationResult) CollectAnnotations() *EvaluationResult {
-	if e.Annotations == nil {
-		e.Annotations = make(map[string]interface{})
-	}
-
 	if e.schema.Title != nil {
-		e.Annotations["title"] = e.schema.Title
+		e.AddAnnotation("title", e.schema.Title)
 	}
 	if e.schema.Description != nil {
-		e.Annotations["description"] = e.schema.Description
+		e.AddAnnotation("description", e.schema.Description)
 	}
 	if e.schema.Default != nil {
-		e.Annotations["default"] = e.schema.Default
+		e.AddAnnotation("default", e.schema.Default)
 	}
 	if e.schema.Deprecated != nil {
-		e.Annotations["deprecated"] = e.schema.Deprecated
+		e.AddAnnotation("deprecated", e.schema.Deprecated)
 	}
 	if e.schema.ReadOnly != nil {
-		e.Annotations["readOnly"] = e.schema.ReadOnly
+		e.AddAnnotation("readOnly", e.schema.ReadOnly)
 	}
 	if e.schema.WriteOnly != nil {
-		e.Annotations["writeOnly"] = e.schema.WriteOnly
+		e.AddAnnotation("writeOnly", e.schema.WriteOnly)
 	}
 	if e.schema.Examples != nil {
-		e.Annotations["examples"] = e.schema.Examples
+		e.AddAnnotation("examples", e.schema.Examples)
 	}
 
 	return e
 }
 
+// locate sets the evaluation path and the instance location of the results evaluated in e, which are
+// only built once the evaluation is over, for the results that were kept.
+func (e *EvaluationResult) locate() *EvaluationResult {
+	if e == nil || !e.pending {
+		return e
+	}
+	e.EvaluationPath = e.evaluationPath.String()
+	e.InstanceLocation = e.instanceLocation.String()
+	e.evaluationPath, e.instanceLocation, e.pending = nil, nil, false
+	for _, detail := range e.Details {
+		detail.locate()
+	}
+	return e
+}
+
 // Converts EvaluationResult to a simple Flag struct
 func (e *EvaluationResult) ToFlag() *Flag {
 	return &Flag{
@@ -199,15 +250,12 @@ func (e *EvaluationResult) ToLocalizeList(localizer *i18n.Localizer, options ...
 		onlyErrors = options[1]
 	}
 
-	// 1. Собираем границы (то, что пришло от родителя + свои собственные из схемы)
-	var currentFacets []string
-	if len(e.XTFFacets) > 0 {
-		currentFacets = append(currentFacets, e.XTFFacets...)
-	}
-	if e.schema != nil && len(e.schema.XTFFacets) > 0 {
-		currentFacets = append(currentFacets, e.schema.XTFFacets...)
-	}
+	return e.toList(localizer, hierarchyIncluded, onlyErrors, nil)
+}
 
+// toList converts the evaluation results into a list, given the facets of the results above them.
+func (e *EvaluationResult) toList(localizer *i18n.Localizer, hierarchyIncluded bool, onlyErrors bool, facets []string) *List {
+	facets = inheritFacets(facets, e)
 	list := &List{
 		Valid:            e.Valid,
 		EvaluationPath:   e.EvaluationPath,
@@ -216,7 +264,7 @@ func (e *EvaluationResult) ToLocalizeList(localizer *i18n.Localizer, options ...
 		Annotations:      e.Annotations,
 		Errors:           e.convertErrors(localizer),
 		Details:          make([]List, 0),
-		XTFFacets:        currentFacets,
+		XTFFacets:        facets,
 	}
 
 	if hierarchyIncluded {
@@ -226,38 +274,24 @@ func (e *EvaluationResult) ToLocalizeList(localizer *i18n.Localizer, options ...
 				continue
 			}
 
-			// Прокидываем состояние вниз: передаем накопленные границы дочернему узлу
-			detail.XTFFacets = currentFacets
-			childList := detail.ToLocalizeList(localizer, true, onlyErrors) // recursively include hierarchy
+			childList := detail.toList(localizer, true, onlyErrors, facets) // recursively include hierarchy
 			list.Details = append(list.Details, *childList)
 		}
 	} else {
-		// Для плоского списка
-		for _, detail := range e.Details {
-			detail.XTFFacets = currentFacets
-		}
-		e.flattenDetailsToList(localizer, list, e.Details, onlyErrors)
+		e.flattenDetailsToList(localizer, list, e.Details, onlyErrors, facets)
 	}
 
 	return list
 }
 
-func (e *EvaluationResult) flattenDetailsToList(localizer *i18n.Localizer, list *List, details []*EvaluationResult, onlyErrors bool) {
+func (e *EvaluationResult) flattenDetailsToList(localizer *i18n.Localizer, list *List, details []*EvaluationResult, onlyErrors bool, facets []string) {
 	for _, detail := range details {
 		// ОТСЕКАЕМ успешные проверки для плоского списка
 		if onlyErrors && detail.Valid {
 			continue
 		}
 
-		// Собираем границы дочернего узла
-		var currentFacets []string
-		if len(detail.XTFFacets) > 0 {
-			currentFacets = append(currentFacets, detail.XTFFacets...)
-		}
-		if detail.schema != nil && len(detail.schema.XTFFacets) > 0 {
-			currentFacets = append(currentFacets, detail.schema.XTFFacets...)
-		}
-
+		detailFacets := inheritFacets(facets, detail)
 		flatDetail := List{
 			Valid:            detail.Valid,
 			EvaluationPath:   detail.EvaluationPath,
@@ -265,19 +299,29 @@ func (e *EvaluationResult) flattenDetailsToList(localizer *i18n.Localizer, list
 			InstanceLocation: detail.InstanceLocation,
 			Annotations:      detail.Annotations,
 			Errors:           detail.convertErrors(localizer),
-			XTFFacets:        currentFacets,
+			XTFFacets:        detailFacets,
 		}
 		list.Details = append(list.Details, flatDetail)
 
 		if len(detail.Details) > 0 {
-			for _, childDetail := range detail.Details {
-				childDetail.XTFFacets = currentFacets
-			}
-			e.flattenDetailsToList(localizer, list, detail.Details, onlyErrors)
+			e.flattenDetailsToList(localizer, list, detail.Details, onlyErrors, detailFacets)
 		}
 	}
 }
 
+// inheritFacets returns the facets of the results above e followed by the facets of its schema. The
+// facets are set by the x-tf-facets keyword, or read from the schema when it was not evaluated.
+func inheritFacets(facets []string, e *EvaluationResult) []string {
+	own := e.XTFFacets
+	if own == nil && e.schema != nil {
+		own = e.schema.XTFFacets
+	}
+	if len(own) == 0 {
+		return facets
+	}
+	return append(append(make([]string, 0, len(facets)+len(own)), facets...), own...)
+}
+
 func (e *EvaluationResult) convertErrors(localizer *i18n.Localizer) map[string]string {
 	errors := make(map[string]string)
 	for key, err := range e.Errors {
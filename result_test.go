@@ -2,6 +2,8 @@ package jsonschema
 
 import (
 	"encoding/json"
+	"errors"
+	"sort"
 	"testing"
 
 	"github.com/test-go/testify/assert"
@@ -216,3 +218,240 @@ func TestToFlag(t *testing.T) {
 	// Verify the validity of the returned flag
 	assert.Equal(t, false, flagInvalid.Valid, "Expected validity of flag to match EvaluationResult validity for an invalid result")
 }
+
+func TestStructuredErrors(t *testing.T) {
+	schema, err := NewCompiler().Compile([]byte(`{
+		"properties": {
+			"name": {"type": "string"},
+			"age": {"type": "integer", "minimum": 18}
+		},
+		"patternProperties": {
+			"^n": {"minLength": 3}
+		},
+		"required": ["name", "age", "email"]
+	}`))
+	assert.NoError(t, err)
+
+	t.Run("ordered errors", func(t *testing.T) {
+		result := schema.Validate(map[string]interface{}{"name": "Al"})
+		assert.False(t, result.IsValid())
+
+		var codes []string
+		for _, err := range result.ErrorList() {
+			codes = append(codes, err.Code())
+		}
+		assert.Equal(t, []string{"property_mismatch", "pattern_property_mismatch", "missing_required_properties"}, codes)
+		assert.Len(t, result.Errors, 2, "Expected the errors map to keep the last error of each keyword")
+	})
+
+	t.Run("accessors", func(t *testing.T) {
+		result := schema.Validate(map[string]interface{}{"name": "Alice", "age": 12, "email": "a@b.c"})
+		violations := result.Violations()
+		if assert.Len(t, violations, 1) {
+			assert.Equal(t, "/age", violations[0].InstancePath)
+			assert.Equal(t, "/properties/age", violations[0].SchemaPath)
+			assert.Equal(t, "minimum", violations[0].Keyword)
+			assert.Equal(t, "value_below_minimum", violations[0].Code)
+			assert.Equal(t, "12 should be at least 18", violations[0].Message)
+			assert.Equal(t, map[string]interface{}{"minimum": "18", "value": "12"}, violations[0].Params)
+			assert.Equal(t, "minimum", violations[0].Err().Keyword())
+		}
+	})
+
+	t.Run("violations", func(t *testing.T) {
+		result := schema.Validate(map[string]interface{}{"name": 7, "age": 20})
+		assert.Equal(t, []Violation{
+			{InstancePath: "/name", SchemaPath: "/properties/name", Keyword: "type", Code: "type_mismatch", Message: "Value is integer but should be string", Params: map[string]interface{}{"expected": "string", "received": "integer"}},
+			{InstancePath: "", SchemaPath: "", Keyword: "required", Code: "missing_required_property", Message: "Required property 'email' is missing", Params: map[string]interface{}{"property": "'email'"}},
+		}, stripViolationErrors(result.Violations()))
+	})
+
+	t.Run("subschemas that do not fail the instance", func(t *testing.T) {
+		schema, err := NewCompiler().Compile([]byte(`{
+			"anyOf": [{"type": "integer"}, {"type": "string"}],
+			"if": {"type": "string"},
+			"else": {"minimum": 10},
+			"not": {"type": "integer", "minimum": 100},
+			"maximum": 5
+		}`))
+		assert.NoError(t, err)
+
+		var codes []string
+		for _, violation := range schema.Validate(float64(7)).Violations() {
+			codes = append(codes, violation.Code)
+		}
+		assert.Equal(t, []string{"value_below_minimum", "value_above_maximum"}, codes)
+
+		codes = nil
+		for _, violation := range schema.Validate(float64(200)).Violations() {
+			codes = append(codes, violation.Code)
+		}
+		assert.Equal(t, []string{"not_schema_mismatch", "value_above_maximum"}, codes)
+	})
+
+	t.Run("errors.Is and errors.As", func(t *testing.T) {
+		assert.NoError(t, schema.Validate(map[string]interface{}{"name": "Alice", "age": 20, "email": "a@b.c"}).Err())
+
+		err := schema.Validate(map[string]interface{}{"name": "Alice", "age": 12.5, "email": "a@b.c"}).Err()
+		assert.True(t, errors.Is(err, ErrInvalidInstance))
+		assert.True(t, errors.Is(err, NewEvaluationError("", "type_mismatch", "")))
+		assert.True(t, errors.Is(err, NewEvaluationError("minimum", "value_below_minimum", "")))
+		assert.False(t, errors.Is(err, NewEvaluationError("maximum", "value_below_minimum", "")))
+		assert.False(t, errors.Is(err, NewEvaluationError("", "missing_required_property", "")))
+		assert.Equal(t, "instance does not conform to the schema: /age: Value is number but should be integer; /age: 12.5 should be at least 18", err.Error())
+
+		var validationErr *ValidationError
+		if assert.True(t, errors.As(err, &validationErr)) {
+			assert.Len(t, validationErr.Violations, 2)
+		}
+		var evaluationErr *EvaluationError
+		if assert.True(t, errors.As(err, &evaluationErr)) {
+			assert.Equal(t, "type", evaluationErr.Keyword())
+		}
+	})
+}
+
+// stripViolationErrors clears the unexported error of violations, so they can be compared to literals.
+func stripViolationErrors(violations []Violation) []Violation {
+	for i := range violations {
+		violations[i].err = nil
+	}
+	return violations
+}
+
+func TestEvaluationLocations(t *testing.T) {
+	schema, err := NewCompiler().Compile([]byte(`{
+		"$id": "https://example.com/person",
+		"$defs": {
+			"age": {"type": "integer", "minimum": 0}
+		},
+		"properties": {
+			"person": {
+				"properties": {
+					"age": {"$ref": "#/$defs/age"},
+					"a/b~c": {"type": "string"}
+				}
+			},
+			"tags": {"items": {"not": {"const": "x"}}},
+			"point": {"if": {"required": ["x"]}, "else": {"required": ["y"]}}
+		}
+	}`))
+	assert.NoError(t, err)
+
+	result := schema.Validate(map[string]interface{}{
+		"person": map[string]interface{}{"age": -1, "a/b~c": 1},
+		"tags":   []interface{}{"a", "x"},
+		"point":  map[string]interface{}{},
+	})
+
+	var locations []string
+	for _, violation := range result.Violations() {
+		locations = append(locations, violation.InstancePath+" "+violation.SchemaPath+" "+violation.Keyword)
+	}
+	sort.Strings(locations)
+	assert.Equal(t, []string{
+		"/person/age /properties/person/properties/age/$ref minimum",
+		"/person/a~1b~0c /properties/person/properties/a~1b~0c type",
+		"/point /properties/point/else required",
+		"/tags/1 /properties/tags/items not",
+	}, locations)
+
+	var find func(result *EvaluationResult, path string, location string) *EvaluationResult
+	find = func(result *EvaluationResult, path string, location string) *EvaluationResult {
+		if result.EvaluationPath == path && result.InstanceLocation == location {
+			return result
+		}
+		for _, detail := range result.Details {
+			if found := find(detail, path, location); found != nil {
+				return found
+			}
+		}
+		return nil
+	}
+
+	age := find(result, "/properties/person/properties/age/$ref", "/person/age")
+	if assert.NotNil(t, age) {
+		assert.Equal(t, "/person/age", age.InstanceLocation)
+		assert.Equal(t, "https://example.com/person#/$defs/age", age.SchemaLocation)
+	}
+	notSchema := find(result, "/properties/tags/items/not", "/tags/1")
+	if assert.NotNil(t, notSchema) {
+		assert.Equal(t, "/tags/1", notSchema.InstanceLocation)
+		assert.Equal(t, "https://example.com/person#/properties/tags/items/not", notSchema.SchemaLocation)
+	}
+	assert.Equal(t, "https://example.com/person#/properties/tags/items", (*schema.Properties)["tags"].Items.GetSchemaLocation(""))
+}
+
+func TestAnnotationsAt(t *testing.T) {
+	schema, err := NewCompiler().Compile([]byte(`{
+		"title": "order",
+		"properties": {
+			"id": {"type": "string", "format": "uuid", "readOnly": true},
+			"note": {"contentMediaType": "application/json", "contentEncoding": "base64"},
+			"lines": {
+				"prefixItems": [{"description": "first line"}],
+				"items": {"type": "object", "title": "line"},
+				"contains": {"type": "object", "required": ["sku"]}
+			}
+		},
+		"patternProperties": {"^x-": {"description": "extension"}},
+		"additionalProperties": {"deprecated": true},
+		"anyOf": [
+			{"title": "rejected", "properties": {"id": {"type": "integer"}}},
+			{"title": "accepted"}
+		]
+	}`))
+	assert.NoError(t, err)
+
+	result := schema.Validate(map[string]interface{}{
+		"id":    "f47ac10b-58cc-4372-a567-0e02b2c3d479",
+		"note":  "eyJhIjoxfQ==",
+		"lines": []interface{}{"first", map[string]interface{}{"sku": "a"}, map[string]interface{}{}},
+		"x-a":   1,
+		"old":   true,
+	})
+	assert.True(t, result.IsValid())
+
+	annotations := func(instancePath string) []string {
+		var list []string
+		for _, annotation := range result.AnnotationsAt(instancePath) {
+			value, err := json.Marshal(annotation.Value)
+			assert.NoError(t, err)
+			list = append(list, annotation.SchemaPath+" "+annotation.Keyword+": "+string(value))
+		}
+		return list
+	}
+
+	// The annotations of the anyOf branch that failed are dropped.
+	assert.Equal(t, []string{
+		` additionalProperties: ["old"]`,
+		` patternProperties: ["x-a"]`,
+		` properties: ["id","lines","note"]`,
+		` title: "order"`,
+		`/anyOf/1 title: "accepted"`,
+	}, annotations(""))
+	assert.Equal(t, []string{
+		`/properties/id format: "uuid"`,
+		`/properties/id readOnly: true`,
+	}, annotations("/id"))
+	assert.Equal(t, []string{
+		`/properties/note contentEncoding: "base64"`,
+		`/properties/note contentMediaType: "application/json"`,
+	}, annotations("/note"))
+	assert.Equal(t, []string{
+		`/properties/lines contains: [1]`,
+		`/properties/lines items: true`,
+		`/properties/lines prefixItems: 0`,
+	}, annotations("/lines"))
+	assert.Equal(t, []string{`/properties/lines/prefixItems/0 description: "first line"`}, annotations("/lines/0"))
+	assert.Equal(t, []string{`/properties/lines/items title: "line"`}, annotations("/lines/1"))
+	assert.Equal(t, []string{`/patternProperties/^x- description: "extension"`}, annotations("/x-a"))
+	assert.Equal(t, []string{`/additionalProperties deprecated: true`}, annotations("/old"))
+	assert.Empty(t, annotations("/missing"))
+
+	// No annotation is kept for an invalid instance, nor collected in fail-fast mode.
+	invalid := schema.Validate(map[string]interface{}{"id": 1, "lines": []interface{}{}})
+	assert.False(t, invalid.IsValid())
+	assert.Empty(t, invalid.AnnotationsAt(""))
+	assert.Empty(t, schema.ValidateFast(map[string]interface{}{"id": "a"}).AnnotationsAt("/id"))
+}
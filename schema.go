@@ -1,7 +1,11 @@
 package jsonschema
 
 import (
-	"regexp"
+	"context"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
 
 	"github.com/goccy/go-json"
 )
@@ -9,18 +13,27 @@ import (
 // Schema represents a JSON Schema as per the 2020-12 draft, containing all
 // necessary metadata and validation properties defined by the specification.
 type Schema struct {
-	compiledPatterns map[string]*regexp.Regexp // Cached compiled regular expressions for pattern properties.
-	compiler         *Compiler                 // Reference to the associated Compiler instance.
-	parent           *Schema                   // Parent schema for hierarchical resolution.
-	uri              string                    // Internal schema identifier resolved during compilation.
-	baseURI          string                    // Base URI for resolving relative references within the schema.
-	anchors          map[string]*Schema        // Anchors for quick lookup of internal schema references.
-	dynamicAnchors   map[string]*Schema        // Dynamic anchors for more flexible schema references.
-	schemas          map[string]*Schema        // Cache of compiled schemas.
-
-	ID     string  `json:"$id,omitempty"`     // Public identifier for the schema.
-	Schema string  `json:"$schema,omitempty"` // URI indicating the specification the schema conforms to.
-	Format *string `json:"format,omitempty"`  // Format hint for string data, e.g., "email" or "date-time".
+	compiledPatterns map[string]Regexp          // Cached compiled regular expressions for pattern properties.
+	compiledPattern  Regexp                     // Compiled regular expression of the pattern keyword.
+	compiler         *Compiler                  // Reference to the associated Compiler instance.
+	parent           *Schema                    // Parent schema for hierarchical resolution.
+	location         string                     // JSON Pointer of the schema within its root document.
+	uri              string                     // Internal schema identifier resolved during compilation.
+	baseURI          string                     // Base URI for resolving relative references within the schema.
+	anchors          map[string]*Schema         // Anchors for quick lookup of internal schema references.
+	dynamicAnchors   map[string]*Schema         // Dynamic anchors for more flexible schema references.
+	schemas          map[string]*Schema         // Cache of compiled schemas.
+	warnings         []*ReferenceError          // Unresolved references tolerated when the schema was compiled.
+	vocabularies     map[string]bool            // Vocabularies active in the schema document, nil for the default dialect.
+	unknownKeywords  map[string]json.RawMessage // Raw values of the keywords without a field, by name.
+	keywords         []*compiledKeyword         // Custom keywords of the compiler used by the schema.
+	plan             *evaluationPlan            // Evaluators of the keywords of the schema.
+
+	ID     string `json:"$id,omitempty"`     // Public identifier for the schema.
+	Schema string `json:"$schema,omitempty"` // URI indicating the specification the schema conforms to.
+
+	Vocabulary map[string]bool `json:"$vocabulary,omitempty"` // Vocabularies of a meta-schema, mapped to whether they are required.
+	Format     *string         `json:"format,omitempty"`      // Format hint for string data, e.g., "email" or "date-time".
 
 	// Schema reference keywords, see https://json-schema.org/draft/2020-12/json-schema-core#ref
 	Ref                string             `json:"$ref,omitempty"`           // Reference to another schema.
@@ -107,8 +120,11 @@ type Schema struct {
 	WriteOnly   *bool         `json:"writeOnly,omitempty"`   // Indicates that the property is write-only.
 	Examples    []interface{} `json:"examples,omitempty"`    // Examples of the instance data that validates against this schema.
 
+	// Deprecated: x-tf-accepted-objects is evaluated by AcceptedObjectsKeyword, registered by NewCompiler.
 	XTFAcceptedObjects []interface{} `json:"x-tf-accepted-objects,omitempty"`
-	XTFFacets          []string      `json:"x-tf-facets,omitempty"`
+	// Deprecated: x-tf-facets is evaluated by FacetsKeyword, registered by NewCompiler, and reported as an
+	// annotation, see EvaluationResult.AnnotationsAt.
+	XTFFacets []string `json:"x-tf-facets,omitempty"`
 }
 
 // newSchema parses JSON schema data and returns a Schema object.
@@ -124,9 +140,13 @@ func newSchema(jsonSchema []byte) (*Schema, error) {
 
 // initializeSchema sets up the schema structure, resolves URIs, and initializes nested schemas.
 // It populates schema properties from the compiler settings and the parent schema context.
-func (s *Schema) initializeSchema(compiler *Compiler, parent *Schema) (err error) {
+// References are resolved afterwards by resolveReferences, once every anchor and $id of the document is known.
+func (s *Schema) initializeSchema(ctx context.Context, compiler *Compiler, parent *Schema) (err error) {
 	s.compiler = compiler
 	s.parent = parent
+	if parent != nil {
+		s.vocabularies = parent.vocabularies
+	}
 
 	parentBaseURI := s.getParentBaseURI()
 	if parentBaseURI == "" {
@@ -164,143 +184,116 @@ func (s *Schema) initializeSchema(compiler *Compiler, parent *Schema) (err error
 		root.setSchema(s.uri, s)
 	}
 
-	err = initializeNestedSchemas(s, compiler)
+	err = s.compileRegexps(compiler)
 	if err != nil {
 		return
 	}
-	err = s.resolveReferences()
+
+	err = s.compileKeywords(compiler)
 	if err != nil {
 		return
 	}
+	s.plan = s.compilePlan()
+
+	err = initializeNestedSchemas(ctx, s, compiler)
 	return
 }
 
 // initializeNestedSchemas initializes all nested or related schemas as defined in the structure.
-func initializeNestedSchemas(s *Schema, compiler *Compiler) (err error) {
-	if s.Defs != nil {
-		for _, def := range s.Defs {
-			err = def.initializeSchema(compiler, s)
-			if err != nil {
-				return
-			}
+func initializeNestedSchemas(ctx context.Context, s *Schema, compiler *Compiler) error {
+	for _, sub := range s.subschemas() {
+		if err := s.locate(sub.schema, sub.segments...).initializeSchema(ctx, compiler, s); err != nil {
+			return err
 		}
 	}
-	// Initialize logical schema groupings
-	err = initializeSchemas(s.AllOf, compiler, s)
-	if err != nil {
-		return
-	}
-	err = initializeSchemas(s.AnyOf, compiler, s)
-	if err != nil {
-		return
-	}
-	err = initializeSchemas(s.OneOf, compiler, s)
-	if err != nil {
-		return
-	}
+	return nil
+}
 
-	// Initialize conditional schemas
-	if s.Not != nil {
-		err = s.Not.initializeSchema(compiler, s)
-		if err != nil {
-			return
-		}
-	}
-	if s.If != nil {
-		err = s.If.initializeSchema(compiler, s)
-		if err != nil {
-			return
+// subschema is a schema nested directly in another one, together with the JSON Pointer segments
+// locating it within its parent, such as ["properties", "name"] or ["items"].
+type subschema struct {
+	schema   *Schema
+	segments []string
+}
+
+// subschemas lists the direct subschemas of s for every keyword that applies subschemas. It is
+// the single walker behind schema initialization, reference resolution and JSON Pointer lookup,
+// so every keyword listed here supports $ref and can be the target of a pointer. Subschemas of
+// object-valued keywords are listed in key order.
+func (s *Schema) subschemas() []subschema {
+	var subs []subschema
+	single := func(keyword string, schema *Schema) {
+		if schema != nil {
+			subs = append(subs, subschema{schema: schema, segments: []string{keyword}})
 		}
 	}
-	if s.Then != nil {
-		err = s.Then.initializeSchema(compiler, s)
-		if err != nil {
-			return
+	list := func(keyword string, schemas []*Schema) {
+		for i, schema := range schemas {
+			if schema != nil {
+				subs = append(subs, subschema{schema: schema, segments: []string{keyword, strconv.Itoa(i)}})
+			}
 		}
 	}
-	if s.Else != nil {
-		err = s.Else.initializeSchema(compiler, s)
-		if err != nil {
-			return
+	object := func(keyword string, schemas map[string]*Schema) {
+		keys := make([]string, 0, len(schemas))
+		for key := range schemas {
+			keys = append(keys, key)
 		}
-	}
-	if s.DependentSchemas != nil {
-		for _, depSchema := range s.DependentSchemas {
-			err = depSchema.initializeSchema(compiler, s)
-			if err != nil {
-				return
+		sort.Strings(keys)
+		for _, key := range keys {
+			if schema := schemas[key]; schema != nil {
+				subs = append(subs, subschema{schema: schema, segments: []string{keyword, key}})
 			}
 		}
 	}
 
-	// Initialize array and object schemas
-	if s.PrefixItems != nil {
-		for _, item := range s.PrefixItems {
-			err = item.initializeSchema(compiler, s)
-			if err != nil {
-				return
-			}
-		}
-	}
-	if s.Items != nil {
-		err = s.Items.initializeSchema(compiler, s)
-		if err != nil {
-			return
-		}
-	}
-	if s.Contains != nil {
-		err = s.Contains.initializeSchema(compiler, s)
-		if err != nil {
-			return
-		}
-	}
-	if s.AdditionalProperties != nil {
-		err = s.AdditionalProperties.initializeSchema(compiler, s)
-		if err != nil {
-			return
-		}
-	}
+	object("$defs", s.Defs)
+	list("allOf", s.AllOf)
+	list("anyOf", s.AnyOf)
+	list("oneOf", s.OneOf)
+	single("not", s.Not)
+	single("if", s.If)
+	single("then", s.Then)
+	single("else", s.Else)
+	object("dependentSchemas", s.DependentSchemas)
+	list("prefixItems", s.PrefixItems)
+	single("items", s.Items)
+	single("contains", s.Contains)
+	single("additionalProperties", s.AdditionalProperties)
 	if s.Properties != nil {
-		for _, prop := range *s.Properties {
-			err = prop.initializeSchema(compiler, s)
-			if err != nil {
-				return
-			}
-		}
+		object("properties", *s.Properties)
 	}
 	if s.PatternProperties != nil {
-		for _, prop := range *s.PatternProperties {
-			err = prop.initializeSchema(compiler, s)
-			if err != nil {
-				return
-			}
-		}
-	}
-	if s.UnevaluatedProperties != nil {
-		err = s.UnevaluatedProperties.initializeSchema(compiler, s)
-		if err != nil {
-			return
-		}
-	}
-	if s.UnevaluatedItems != nil {
-		err = s.UnevaluatedItems.initializeSchema(compiler, s)
-		if err != nil {
-			return
-		}
+		object("patternProperties", *s.PatternProperties)
 	}
-	if s.ContentSchema != nil {
-		err = s.ContentSchema.initializeSchema(compiler, s)
-		if err != nil {
-			return
+	single("unevaluatedProperties", s.UnevaluatedProperties)
+	single("unevaluatedItems", s.UnevaluatedItems)
+	single("contentSchema", s.ContentSchema)
+	single("propertyNames", s.PropertyNames)
+	subs = append(subs, s.keywordSubschemas()...)
+
+	return subs
+}
+
+// subschemaAt returns the direct subschema of s located by the leading JSON Pointer segments,
+// along with the number of segments it consumed.
+func (s *Schema) subschemaAt(segments []string) (*Schema, int) {
+	for _, sub := range s.subschemas() {
+		if len(sub.segments) <= len(segments) && slices.Equal(sub.segments, segments[:len(sub.segments)]) {
+			return sub.schema, len(sub.segments)
 		}
 	}
-	if s.PropertyNames != nil {
-		err = s.PropertyNames.initializeSchema(compiler, s)
-		if err != nil {
-			return
-		}
+	return nil, 0
+}
+
+// locate records the JSON Pointer of a nested schema, built from the location of s and the given keyword path.
+func (s *Schema) locate(nested *Schema, segments ...string) *Schema {
+	location := s.location
+	for _, segment := range segments {
+		location += "/" + escapeJSONPointerSegment(segment)
 	}
-	return
+	nested.location = location
+	return nested
 }
 
 // setAnchor creates or updates the anchor mapping for the current schema and propagates it to parent schemas.
@@ -362,17 +355,10 @@ func (s *Schema) getSchema(ref string) (*Schema, error) {
 	return nil, ErrFailedToResolveReference
 }
 
-// initializeSchemas iteratively initializes a list of nested schemas.
-func initializeSchemas(schemas []*Schema, compiler *Compiler, parent *Schema) (err error) {
-	for _, schema := range schemas {
-		if schema != nil {
-			err = schema.initializeSchema(compiler, parent)
-			if err != nil {
-				return
-			}
-		}
-	}
-	return
+// CompileWarnings returns the references that could not be resolved when the schema was compiled
+// in lenient mode, ordered by location. Evaluation ignores unresolved references.
+func (s *Schema) CompileWarnings() []*ReferenceError {
+	return s.warnings
 }
 
 // GetSchemaURI returns the resolved URI for the schema, or an empty string if no URI is defined.
@@ -388,10 +374,17 @@ func (s *Schema) GetSchemaURI() string {
 	return ""
 }
 
+// GetSchemaLocation returns the URI of the keyword of s found at the given JSON Pointer, made of the URI
+// of the schema resource holding s and of a fragment locating the keyword within that resource. Without
+// a URI, only the fragment is returned.
 func (s *Schema) GetSchemaLocation(anchor string) string {
-	uri := s.GetSchemaURI()
+	scope := s.getScopeSchema()
+	uri := scope.GetSchemaURI()
+	if i := strings.IndexByte(uri, '#'); i >= 0 {
+		uri = uri[:i]
+	}
 
-	return uri + "#" + anchor
+	return uri + "#" + strings.TrimPrefix(s.location, scope.location) + anchor
 }
 
 // getRootSchema returns the highest-level parent schema, serving as the root in the schema tree.
@@ -451,6 +444,21 @@ func (s *Schema) UnmarshalJSON(data []byte) error {
 			return err
 		}
 	}
+
+	// Keep the keywords without a field, which custom keywords are compiled from.
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	for name, value := range raw {
+		if schemaKeywords[name] {
+			continue
+		}
+		if s.unknownKeywords == nil {
+			s.unknownKeywords = make(map[string]json.RawMessage)
+		}
+		s.unknownKeywords[name] = value
+	}
 	return nil
 }
 
@@ -460,11 +468,24 @@ func (s *Schema) MarshalJSON() ([]byte, error) {
 		return json.Marshal(s.Boolean)
 	}
 	type Alias Schema
-	return json.Marshal(&struct {
+	data, err := json.Marshal(&struct {
 		*Alias
 	}{
 		Alias: (*Alias)(s),
 	})
+	if err != nil || len(s.unknownKeywords) == 0 {
+		return data, err
+	}
+
+	// Keep the keywords without a field, such as the custom ones.
+	var members map[string]json.RawMessage
+	if err := json.Unmarshal(data, &members); err != nil {
+		return nil, err
+	}
+	for name, value := range s.unknownKeywords {
+		members[name] = value
+	}
+	return json.Marshal(members)
 }
 
 // SchemaMap represents a map of string keys to *Schema values, used primarily for properties and patternProperties.
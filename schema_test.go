@@ -1,7 +1,13 @@
 package jsonschema
 
 import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/test-go/testify/assert"
 )
@@ -12,8 +18,8 @@ func TestGetRootSchema(t *testing.T) {
 	child := &Schema{ID: "child"}
 	grandChild := &Schema{ID: "grandChild"}
 
-	child.initializeSchema(compiler, root)
-	grandChild.initializeSchema(compiler, child)
+	child.initializeSchema(context.Background(), compiler, root)
+	grandChild.initializeSchema(context.Background(), compiler, child)
 
 	if grandChild.getRootSchema().ID != "root" {
 		t.Errorf("Expected root schema ID to be 'root', got '%s'", grandChild.getRootSchema().ID)
@@ -65,3 +71,261 @@ func TestSchemaInitialization(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateConcurrently(t *testing.T) {
+	compiler := NewCompiler()
+	compiler.RegisterLoader("tf", func(url string) (io.ReadCloser, error) {
+		return io.NopCloser(strings.NewReader(`{"type": "object", "required": ["name"]}`)), nil
+	})
+
+	schema, err := compiler.Compile([]byte(`{
+		"type": "object",
+		"properties": {
+			"tags": {
+				"type": "object",
+				"patternProperties": {"^x-": {"type": "string"}},
+				"additionalProperties": false
+			},
+			"owner": {"$ref": "tf://types/any"}
+		}
+	}`))
+	assert.NoError(t, err)
+
+	instances := []struct {
+		instance map[string]interface{}
+		valid    bool
+	}{
+		{map[string]interface{}{"tags": map[string]interface{}{"x-a": "b"}}, true},
+		{map[string]interface{}{"tags": map[string]interface{}{"x-a": 1}}, false},
+		{map[string]interface{}{"tags": map[string]interface{}{"y": "b"}}, false},
+		{map[string]interface{}{"owner": map[string]interface{}{"@schema": "tf://types/person", "name": "John"}}, true},
+		{map[string]interface{}{"owner": map[string]interface{}{"@schema": "tf://types/person"}}, false},
+		{map[string]interface{}{"owner": map[string]interface{}{"name": "John"}}, false},
+	}
+
+	owner := (*schema.Properties)["owner"]
+	compiledRef := owner.ResolvedRef
+
+	var wg sync.WaitGroup
+	for worker := 0; worker < 8; worker++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 50; i++ {
+				for _, tc := range instances {
+					if result := schema.Validate(tc.instance); result.IsValid() != tc.valid {
+						t.Errorf("Expected validity %v for %v, got %v", tc.valid, tc.instance, result.IsValid())
+					}
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.True(t, compiledRef == owner.ResolvedRef, "Validation must not modify the compiled schema")
+}
+
+func TestValidateParentConcurrently(t *testing.T) {
+	compiler := NewCompiler()
+	compiler.RegisterLoader("tf", func(url string) (io.ReadCloser, error) {
+		if strings.HasPrefix(url, "tf://objects/broken") {
+			return io.NopCloser(strings.NewReader(`{"kind": `)), nil
+		}
+		return io.NopCloser(strings.NewReader(`{"kind": "person", "name": "Parent"}`)), nil
+	})
+
+	schema, err := compiler.Compile([]byte(`{
+		"type": "object",
+		"properties": {"kind": {"const": "person"}},
+		"required": ["kind", "name"]
+	}`))
+	assert.NoError(t, err)
+
+	instance := map[string]interface{}{"@parent": "tf://objects/parent", "name": "John"}
+	broken := map[string]interface{}{"@parent": "tf://objects/broken", "name": "John"}
+
+	var wg sync.WaitGroup
+	for worker := 0; worker < 8; worker++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 50; i++ {
+				if result := schema.Validate(instance); !result.IsValid() {
+					t.Errorf("Expected the instance completed by its parent to be valid, got %v", result.ToList().Errors)
+				}
+				if result := schema.Validate(broken); result.IsValid() {
+					t.Error("Expected an unreadable parent to make the instance invalid")
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, map[string]interface{}{"@parent": "tf://objects/parent", "name": "John"}, instance)
+	violations := schema.Validate(broken).Violations()
+	if assert.Len(t, violations, 1) {
+		assert.Equal(t, "parent_cant_reach", violations[0].Code)
+	}
+}
+
+func TestValidateContextCanceled(t *testing.T) {
+	compiler := NewCompiler()
+	schema, err := compiler.Compile([]byte(`{
+		"type": "object",
+		"properties": {
+			"items": {"type": "array", "items": {"type": "integer"}}
+		}
+	}`))
+	assert.NoError(t, err)
+
+	instance := map[string]interface{}{"items": []interface{}{1, 2, 3}}
+	assert.True(t, schema.ValidateContext(context.Background(), instance).IsValid())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	result := schema.ValidateContext(ctx, instance)
+	assert.False(t, result.IsValid())
+	assert.Contains(t, result.Errors, "context")
+}
+
+func TestValidateContextCanceledWithinArray(t *testing.T) {
+	for _, keyword := range []string{"items", "unevaluatedItems"} {
+		t.Run(keyword, func(t *testing.T) {
+			var cancel context.CancelFunc
+			compiler := NewCompiler().SetAssertFormat(true).RegisterFormat("cancel", func(v interface{}) bool {
+				cancel()
+				return true
+			})
+			schema, err := compiler.Compile([]byte(`{"` + keyword + `": {"format": "cancel", "type": "string"}}`))
+			assert.NoError(t, err)
+
+			var ctx context.Context
+			ctx, cancel = context.WithCancel(context.Background())
+			defer cancel()
+
+			// The validation is canceled while checking the first item, before the invalid second one.
+			result := schema.ValidateContext(ctx, []interface{}{"a", 1})
+			assert.False(t, result.IsValid())
+			assert.Contains(t, result.Errors, "context")
+		})
+	}
+}
+
+func TestValidateFast(t *testing.T) {
+	schema, err := NewCompiler().Compile([]byte(`{
+		"title": "list",
+		"type": "array",
+		"items": {"type": "integer", "minimum": 0},
+		"maxItems": 2
+	}`))
+	assert.NoError(t, err)
+
+	result := schema.ValidateFast([]interface{}{1, 2})
+	assert.True(t, result.IsValid())
+	assert.Empty(t, result.Annotations)
+	assert.Empty(t, result.Details)
+
+	instance := []interface{}{1, "a", -1, 3.5}
+	assert.Len(t, schema.Validate(instance).Details, 4)
+
+	result = schema.ValidateFast(instance)
+	assert.False(t, result.IsValid())
+	assert.Len(t, result.Errors, 1)
+	assert.Contains(t, result.Errors, "items")
+	assert.Len(t, result.Details, 1)
+
+	item := result.Details[0]
+	assert.Equal(t, "/items", item.EvaluationPath)
+	assert.Equal(t, "/1", item.InstanceLocation)
+	assert.Len(t, item.Errors, 1)
+	assert.Contains(t, item.Errors, "type")
+
+	result = schema.ValidateContext(WithFailFast(context.Background()), instance)
+	assert.Len(t, result.Errors, 1)
+}
+
+func TestValidateContextTFSchemaLookup(t *testing.T) {
+	compiler := NewCompiler()
+	compiler.RegisterLoaderContext("tf", func(ctx context.Context, url string) (io.ReadCloser, error) {
+		if url == "tf://types/any" {
+			return io.NopCloser(strings.NewReader(`{}`)), nil
+		}
+		<-ctx.Done()
+		return nil, ctx.Err()
+	})
+
+	schema, err := compiler.Compile([]byte(`{"$ref": "tf://types/any"}`))
+	assert.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	result := schema.ValidateContext(ctx, map[string]interface{}{"@schema": "tf://types/slow"})
+	assert.False(t, result.IsValid())
+	assert.Contains(t, result.Errors, "@schema")
+}
+
+func TestRefsInEverySubschemaKeyword(t *testing.T) {
+	schema, err := NewCompiler().SetStrictRefs(true).Compile([]byte(`{
+		"$defs": {"short": {"maxLength": 3}, "number": {"type": "number"}},
+		"if": {"$ref": "#/$defs/number"},
+		"then": {"$ref": "#/$defs/number", "minimum": 10},
+		"else": {"$ref": "#/$defs/short"},
+		"dependentSchemas": {"a": {"type": "null"}},
+		"propertyNames": {"$ref": "#/$defs/short"},
+		"unevaluatedProperties": {"$ref": "#/then"},
+		"unevaluatedItems": {"$ref": "#/dependentSchemas/a"},
+		"contentSchema": {"$ref": "#/else"},
+		"items": {"$ref": "#/propertyNames"},
+		"contains": {"$ref": "#/items"},
+		"not": {"$ref": "#/unevaluatedItems"}
+	}`))
+	assert.NoError(t, err)
+
+	assert.True(t, schema.If.ResolvedRef == schema.Defs["number"])
+	assert.True(t, schema.Then.ResolvedRef == schema.Defs["number"])
+	assert.True(t, schema.Else.ResolvedRef == schema.Defs["short"])
+	assert.True(t, schema.PropertyNames.ResolvedRef == schema.Defs["short"])
+	assert.True(t, schema.UnevaluatedProperties.ResolvedRef == schema.Then)
+	assert.True(t, schema.UnevaluatedItems.ResolvedRef == schema.DependentSchemas["a"])
+	assert.True(t, schema.ContentSchema.ResolvedRef == schema.Else)
+	assert.True(t, schema.Items.ResolvedRef == schema.PropertyNames)
+	assert.True(t, schema.Contains.ResolvedRef == schema.Items)
+	assert.True(t, schema.Not.ResolvedRef == schema.UnevaluatedItems)
+
+	assert.True(t, schema.Validate(12).IsValid())
+	assert.False(t, schema.Validate(5).IsValid())
+	assert.True(t, schema.Validate("abc").IsValid())
+	assert.False(t, schema.Validate("abcd").IsValid())
+	assert.False(t, schema.Validate(map[string]interface{}{"long": 1}).IsValid())
+	assert.False(t, schema.Validate(nil).IsValid())
+}
+
+func TestResolveJSONPointer(t *testing.T) {
+	schema, err := NewCompiler().Compile([]byte(`{
+		"$defs": {"": {"$defs": {"a/b": {"type": "string"}}}},
+		"prefixItems": [true, {"type": "integer"}],
+		"dependentSchemas": {"x~y": {"type": "object"}}
+	}`))
+	assert.NoError(t, err)
+
+	testCases := []struct {
+		pointer  string
+		expected *Schema
+	}{
+		{pointer: "/$defs//$defs/a~1b", expected: schema.Defs[""].Defs["a/b"]},
+		{pointer: "/prefixItems/1", expected: schema.PrefixItems[1]},
+		{pointer: "/dependentSchemas/x~0y", expected: schema.DependentSchemas["x~y"]},
+	}
+	for _, tc := range testCases {
+		resolved, err := schema.resolveJSONPointer(tc.pointer)
+		assert.NoError(t, err, tc.pointer)
+		assert.True(t, resolved == tc.expected, "Unexpected schema for %s", tc.pointer)
+	}
+
+	for _, pointer := range []string{"/prefixItems/2", "/$defs/missing", "/type", "/dependentSchemas"} {
+		_, err := schema.resolveJSONPointer(pointer)
+		assert.True(t, errors.Is(err, ErrSegmentNotFoundForJSONPointer), "Expected %s not to resolve, got %v", pointer, err)
+	}
+}
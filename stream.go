@@ -0,0 +1,360 @@
+package jsonschema
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/goccy/go-json"
+)
+
+// StreamError is an error found by ValidateStream, reported as soon as the value it concerns has been read.
+type StreamError struct {
+	InstanceLocation string           // JSON Pointer of the value, from the root of the document.
+	EvaluationPath   string           // Keywords followed from the root schema to the schema the value fails.
+	Err              *EvaluationError // Error of the failing keyword.
+}
+
+// Error implements the error interface.
+func (e *StreamError) Error() string {
+	location := e.InstanceLocation
+	if location == "" {
+		location = "/"
+	}
+	return fmt.Sprintf("%s: %s", location, e.Err.Error())
+}
+
+// Unwrap returns the error of the failing keyword.
+func (e *StreamError) Unwrap() error {
+	return e.Err
+}
+
+// ValidateStream checks if the JSON document read from r conforms to the schema without decoding it
+// whole, so that documents larger than the available memory can be validated. Arrays and objects are
+// checked as their items and members are read against "type", "properties", "patternProperties",
+// "additionalProperties", "propertyNames", "required", "dependentRequired", "prefixItems", "items" and
+// the size keywords, following "$ref" and "allOf". Scalars are evaluated as soon as they are read, with
+// every keyword. Only the arrays and objects a keyword needs whole, such as "uniqueItems", "contains",
+// "oneOf", "enum" or the unevaluated* keywords, are decoded in memory before being evaluated. The "@parent"
+// member of objects is not merged with the parent it references, which would need the object whole.
+//
+// Numbers are decoded as json.Number, and onError, which may be nil, is called with the errors of the
+// failing keywords as they are found. ValidateStream reports whether the document is valid. A malformed
+// document returns a *JSONParseError, after the errors found in the part of it read before are reported.
+func (s *Schema) ValidateStream(r io.Reader, onError func(*StreamError)) (bool, error) {
+	v := &streamValidator{
+		decoder: newJSONDecoder(r),
+		scope:   NewDynamicScope(),
+		onError: onError,
+		valid:   true,
+	}
+
+	if err := v.value([]*streamSchema{{schema: s}}, 0); err != nil {
+		return false, err
+	}
+	if err := v.decoder.end(); err != nil {
+		return false, err
+	}
+	return v.valid, nil
+}
+
+// streamSchema is a schema applying to the value being read by a streamValidator.
+type streamSchema struct {
+	schema *Schema
+	path   string        // Evaluation path of the schema.
+	parent *streamSchema // Schema the schema was reached from, which precedes it in the dynamic scope.
+}
+
+// child returns the subschema of the schema found at the given keyword path.
+func (e *streamSchema) child(schema *Schema, keywordPath string) *streamSchema {
+	return &streamSchema{schema: schema, path: e.path + keywordPath, parent: e}
+}
+
+// dynamicScope returns the schemas the schema was reached through, starting from the root schema.
+func (e *streamSchema) dynamicScope() []*Schema {
+	var schemas []*Schema
+	for parent := e.parent; parent != nil; parent = parent.parent {
+		schemas = append(schemas, parent.schema)
+	}
+	for i, j := 0, len(schemas)-1; i < j; i, j = i+1, j-1 {
+		schemas[i], schemas[j] = schemas[j], schemas[i]
+	}
+	return schemas
+}
+
+// streamValidator evaluates the values of a JSON document as they are decoded.
+type streamValidator struct {
+	decoder *jsonDecoder
+	scope   *DynamicScope // Tracks the location of the value being read.
+	onError func(*StreamError)
+	valid   bool
+}
+
+// value reads the next value, at the given nesting depth, and evaluates it against schemas.
+func (v *streamValidator) value(schemas []*streamSchema, depth int) error {
+	token, err := v.decoder.token()
+	if err != nil {
+		return err
+	}
+	delim, ok := token.(json.Delim)
+	if !ok {
+		v.evaluate(schemas, token)
+		return nil
+	}
+
+	depth++
+	applicable, ok := streamableSchemas(schemas)
+	if !ok {
+		value, err := v.decoder.container(delim, depth)
+		if err != nil {
+			return err
+		}
+		v.evaluate(schemas, value)
+		return nil
+	}
+
+	if err := v.decoder.nest(depth); err != nil {
+		return err
+	}
+	if delim == '[' {
+		return v.array(applicable, depth)
+	}
+	return v.object(applicable, depth)
+}
+
+// object reads the members of an object and its closing brace, evaluating them as they are read.
+func (v *streamValidator) object(schemas []*streamSchema, depth int) error {
+	keys := map[string]interface{}{}
+	for _, entry := range schemas {
+		v.evaluateStart(entry, keys)
+	}
+
+	for v.decoder.decoder.More() {
+		key, err := v.decoder.key(keys)
+		if err != nil {
+			return err
+		}
+		keys[key] = nil
+
+		v.scope.enterInstance(key)
+		var children []*streamSchema
+		for _, entry := range schemas {
+			children = v.propertySchemas(entry, key, children)
+		}
+		err = v.value(children, depth)
+		v.scope.leaveInstance()
+		if err != nil {
+			return err
+		}
+	}
+	if _, err := v.decoder.token(); err != nil {
+		return err
+	}
+
+	for _, entry := range schemas {
+		if entry.schema.Boolean != nil || !entry.schema.hasVocabulary(VocabularyValidation) {
+			continue
+		}
+		s := entry.schema
+		if s.MaxProperties != nil {
+			v.reportError(entry, evaluateMaxProperties(s, keys))
+		}
+		if s.MinProperties != nil {
+			v.reportError(entry, evaluateMinProperties(s, keys))
+		}
+		if len(s.Required) > 0 {
+			v.reportError(entry, evaluateRequired(s, keys, AnyDirection))
+		}
+		if len(s.DependentRequired) > 0 {
+			v.reportError(entry, evaluateDependentRequired(s, keys))
+		}
+	}
+	return nil
+}
+
+// propertySchemas appends the subschemas of entry applying to the member named key to children, after
+// evaluating key against "propertyNames".
+func (v *streamValidator) propertySchemas(entry *streamSchema, key string, children []*streamSchema) []*streamSchema {
+	s := entry.schema
+	if s.Boolean != nil || !s.hasVocabulary(VocabularyApplicator) {
+		return children
+	}
+
+	if s.PropertyNames != nil {
+		v.evaluate([]*streamSchema{entry.child(s.PropertyNames, "/propertyNames")}, key)
+	}
+
+	matched := false
+	if s.Properties != nil {
+		if schema, ok := (*s.Properties)[key]; ok {
+			children = append(children, entry.child(schema, "/properties/"+escapeJSONPointerSegment(key)))
+			matched = true
+		}
+	}
+	if s.PatternProperties != nil {
+		patterns := make([]string, 0, len(*s.PatternProperties))
+		for pattern := range *s.PatternProperties {
+			patterns = append(patterns, pattern)
+		}
+		sort.Strings(patterns)
+
+		for _, pattern := range patterns {
+			if regex, ok := s.compiledPatterns[pattern]; ok && regex.MatchString(key) {
+				children = append(children, entry.child((*s.PatternProperties)[pattern], "/patternProperties/"+escapeJSONPointerSegment(pattern)))
+				matched = true
+			}
+		}
+	}
+	if !matched && s.AdditionalProperties != nil {
+		children = append(children, entry.child(s.AdditionalProperties, "/additionalProperties"))
+	}
+	return children
+}
+
+// array reads the items of an array and its closing bracket, evaluating them as they are read.
+func (v *streamValidator) array(schemas []*streamSchema, depth int) error {
+	for _, entry := range schemas {
+		v.evaluateStart(entry, []interface{}{})
+	}
+
+	count := 0
+	for ; v.decoder.decoder.More(); count++ {
+		var children []*streamSchema
+		for _, entry := range schemas {
+			s := entry.schema
+			if s.Boolean != nil || !s.hasVocabulary(VocabularyApplicator) {
+				continue
+			}
+			if count < len(s.PrefixItems) {
+				children = append(children, entry.child(s.PrefixItems[count], "/prefixItems/"+strconv.Itoa(count)))
+			} else if s.Items != nil {
+				children = append(children, entry.child(s.Items, "/items"))
+			}
+		}
+
+		v.scope.enterInstance(strconv.Itoa(count))
+		err := v.value(children, depth)
+		v.scope.leaveInstance()
+		if err != nil {
+			return err
+		}
+	}
+	if _, err := v.decoder.token(); err != nil {
+		return err
+	}
+
+	for _, entry := range schemas {
+		if entry.schema.Boolean != nil || !entry.schema.hasVocabulary(VocabularyValidation) {
+			continue
+		}
+		v.reportError(entry, evaluateMaxItems(entry.schema, count))
+		v.reportError(entry, evaluateMinItems(entry.schema, count))
+	}
+	return nil
+}
+
+// evaluateStart evaluates the keywords of entry which only depend on the type of a container, whose
+// empty value is given, as soon as the container is opened.
+func (v *streamValidator) evaluateStart(entry *streamSchema, empty interface{}) {
+	s := entry.schema
+	if s.Boolean != nil {
+		v.reportError(entry, s.evaluateBoolean(empty, map[string]bool{}, map[int]bool{}))
+		return
+	}
+	if s.Type != nil && s.hasVocabulary(VocabularyValidation) {
+		v.reportError(entry, evaluateType(s, empty))
+	}
+}
+
+// evaluate evaluates a value read whole against schemas, reporting the errors of the failing keywords.
+func (v *streamValidator) evaluate(schemas []*streamSchema, value interface{}) {
+	for _, entry := range schemas {
+		scope := entry.dynamicScope()
+		v.scope.schemas = scope[:len(scope):len(scope)]
+		result, _, _ := entry.schema.evaluateIn(value, entry.path, v.scope)
+		v.scope.schemas = nil
+
+		v.report(result.locate())
+	}
+}
+
+// report reports the errors of the innermost failing results of result, which was evaluated on the
+// value being read.
+func (v *streamValidator) report(result *EvaluationResult) {
+	if !result.IsValid() {
+		v.valid = false
+	}
+	walkFailures(result, func(result *EvaluationResult, err *EvaluationError) {
+		v.emit(&StreamError{InstanceLocation: result.InstanceLocation, EvaluationPath: result.EvaluationPath, Err: err})
+	})
+}
+
+// reportError reports the error of a keyword of entry evaluated on the value being read, if any.
+func (v *streamValidator) reportError(entry *streamSchema, err *EvaluationError) {
+	if err == nil {
+		return
+	}
+	v.valid = false
+	v.emit(&StreamError{InstanceLocation: v.scope.InstanceLocation(), EvaluationPath: entry.path, Err: err})
+}
+
+// emit passes err to the callback of the validation.
+func (v *streamValidator) emit(err *StreamError) {
+	if v.onError != nil {
+		v.onError(err)
+	}
+}
+
+// streamableSchemas returns the schemas applying to an array or object, that is the given schemas, the
+// schemas they reference and their allOf members, and tells whether all of them can be evaluated on the
+// items or members of the array or object as they are read.
+func streamableSchemas(schemas []*streamSchema) ([]*streamSchema, bool) {
+	var applicable []*streamSchema
+	var add func(entry *streamSchema) bool
+	add = func(entry *streamSchema) bool {
+		s := entry.schema
+		if !isStreamable(s) {
+			return false
+		}
+		applicable = append(applicable, entry)
+
+		if s.ResolvedRef != nil && !add(entry.child(s.ResolvedRef, "/$ref")) {
+			return false
+		}
+		if s.AllOf != nil && s.hasVocabulary(VocabularyApplicator) {
+			for i, member := range s.AllOf {
+				if !add(entry.child(member, "/allOf/"+strconv.Itoa(i))) {
+					return false
+				}
+			}
+		}
+		return true
+	}
+
+	for _, entry := range schemas {
+		if !add(entry) {
+			return nil, false
+		}
+	}
+	return applicable, true
+}
+
+// isStreamable tells whether none of the keywords of s needs an array or object whole to evaluate it.
+func isStreamable(s *Schema) bool {
+	if s.Boolean != nil {
+		return true
+	}
+	return !strings.HasPrefix(s.Ref, "tf://") &&
+		s.ResolvedDynamicRef == nil &&
+		s.AnyOf == nil && s.OneOf == nil && s.Not == nil &&
+		s.If == nil && s.Then == nil && s.Else == nil &&
+		s.DependentSchemas == nil &&
+		s.Contains == nil && s.MinContains == nil && s.MaxContains == nil &&
+		(s.UniqueItems == nil || !*s.UniqueItems) &&
+		s.UnevaluatedProperties == nil && s.UnevaluatedItems == nil &&
+		s.Enum == nil && s.Const == nil &&
+		s.Format == nil &&
+		len(s.keywords) == 0
+}
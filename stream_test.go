@@ -0,0 +1,109 @@
+package jsonschema
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/test-go/testify/assert"
+)
+
+func TestValidateStream(t *testing.T) {
+	schema, err := NewCompiler().Compile([]byte(`{
+		"$defs": {
+			"tag": {"type": "string", "minLength": 2}
+		},
+		"type": "object",
+		"required": ["users", "total"],
+		"properties": {
+			"users": {
+				"type": "array",
+				"maxItems": 2,
+				"items": {
+					"type": "object",
+					"required": ["name"],
+					"properties": {
+						"name": {"type": "string"},
+						"age": {"type": "integer", "minimum": 0},
+						"tags": {"items": {"$ref": "#/$defs/tag"}, "uniqueItems": true},
+						"labels": {"items": {"$ref": "#/$defs/tag"}}
+					},
+					"additionalProperties": false
+				}
+			},
+			"total": {"type": "integer"}
+		}
+	}`))
+	assert.NoError(t, err)
+
+	var streamErrors []string
+	collect := func(err *StreamError) {
+		streamErrors = append(streamErrors, err.InstanceLocation+" "+err.EvaluationPath+": "+err.Err.Error())
+	}
+
+	t.Run("valid", func(t *testing.T) {
+		streamErrors = nil
+		valid, err := schema.ValidateStream(strings.NewReader(`{"users": [{"name": "Ann", "age": 30, "tags": ["ab", "cd"]}], "total": 1}`), collect)
+		assert.NoError(t, err)
+		assert.True(t, valid)
+		assert.Empty(t, streamErrors)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		streamErrors = nil
+		valid, err := schema.ValidateStream(strings.NewReader(`{
+			"users": [
+				{"age": -1, "a/b": true},
+				{"name": 7, "tags": ["ab", "ab"]},
+				{"name": "Bob", "labels": ["x", "yz"], "age": 1.5}
+			]
+		}`), collect)
+		assert.NoError(t, err)
+		assert.False(t, valid)
+		assert.Equal(t, []string{
+			"/users/0/age /properties/users/items/properties/age: -1 should be at least 0",
+			"/users/0/a~1b /properties/users/items/additionalProperties: No values are allowed because the schema is set to 'false'",
+			"/users/0 /properties/users/items: Required property 'name' is missing",
+			"/users/1/name /properties/users/items/properties/name: Value is integer but should be string",
+			"/users/1/tags /properties/users/items/properties/tags: Found duplicates at the following index groups: (1, 2)",
+			"/users/2/labels/0 /properties/users/items/properties/labels/items/$ref: Value should be at least 2 characters",
+			"/users/2/age /properties/users/items/properties/age: Value is number but should be integer",
+			"/users /properties/users: Value should have at most 2 items",
+			" : Required property 'total' is missing",
+		}, streamErrors)
+	})
+
+	t.Run("errors are reported as they are found", func(t *testing.T) {
+		reader, writer := io.Pipe()
+		found := make(chan string)
+		done := make(chan bool)
+		go func() {
+			valid, err := schema.ValidateStream(reader, func(err *StreamError) {
+				found <- err.InstanceLocation
+			})
+			assert.NoError(t, err)
+			done <- valid
+		}()
+
+		_, err := io.WriteString(writer, `{"total": 1.5, "users": [`)
+		assert.NoError(t, err)
+		assert.Equal(t, "/total", <-found)
+
+		_, err = io.WriteString(writer, `{"name": "Ann"}]}`)
+		assert.NoError(t, err)
+		assert.NoError(t, writer.Close())
+		assert.False(t, <-done)
+	})
+
+	t.Run("malformed document", func(t *testing.T) {
+		streamErrors = nil
+		valid, err := schema.ValidateStream(strings.NewReader(`{"total": "1", "users": [}`), collect)
+		assert.False(t, valid)
+		assert.True(t, errors.Is(err, ErrInvalidJSON), "Expected ErrInvalidJSON, got %v", err)
+		assert.Equal(t, []string{"/total /properties/total: Value is string but should be integer"}, streamErrors)
+
+		_, err = schema.ValidateStream(strings.NewReader(`{"total": 1, "total": 2}`), nil)
+		assert.True(t, errors.Is(err, ErrDuplicateKey), "Expected ErrDuplicateKey, got %v", err)
+	})
+}
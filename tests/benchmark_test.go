@@ -0,0 +1,89 @@
+package tests
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/goccy/go-json"
+
+	"github.com/kaptinlin/jsonschema"
+)
+
+// suiteCase is a compiled schema of the JSON Schema Test Suite with the instances of its tests.
+type suiteCase struct {
+	schema    *jsonschema.Schema
+	instances []interface{}
+}
+
+// loadTestSuite compiles the required draft 2020-12 tests of the JSON Schema Test Suite.
+func loadTestSuite(b *testing.B) []suiteCase {
+	b.Helper()
+
+	files, err := filepath.Glob("../testdata/JSON-Schema-Test-Suite/tests/draft2020-12/*.json")
+	if err != nil || len(files) == 0 {
+		b.Fatalf("Failed to list test files: %v", err)
+	}
+
+	type TestCase struct {
+		Schema json.RawMessage `json:"schema"`
+		Tests  []struct {
+			Data interface{} `json:"data"`
+		} `json:"tests"`
+	}
+
+	var cases []suiteCase
+	for _, file := range files {
+		data, err := os.ReadFile(file) //nolint:gosec
+		if err != nil {
+			b.Fatalf("Failed to read test file: %s", err)
+		}
+		var testCases []TestCase
+		if err := json.Unmarshal(data, &testCases); err != nil {
+			b.Fatalf("Failed to unmarshal test cases: %s", err)
+		}
+
+		for _, tc := range testCases {
+			schema, err := jsonschema.NewCompiler().Compile(tc.Schema)
+			if err != nil {
+				b.Fatalf("Failed to compile schema of %s: %s", file, err)
+			}
+			c := suiteCase{schema: schema}
+			for _, test := range tc.Tests {
+				c.instances = append(c.instances, test.Data)
+			}
+			cases = append(cases, c)
+		}
+	}
+	return cases
+}
+
+// BenchmarkTestSuite validates every instance of the JSON Schema Test Suite.
+func BenchmarkTestSuite(b *testing.B) {
+	server := startTestServer()
+	defer stopTestServer(server)
+
+	cases := loadTestSuite(b)
+
+	b.Run("Validate", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			for _, c := range cases {
+				for _, instance := range c.instances {
+					c.schema.Validate(instance)
+				}
+			}
+		}
+	})
+
+	b.Run("ValidateFast", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			for _, c := range cases {
+				for _, instance := range c.instances {
+					c.schema.ValidateFast(instance)
+				}
+			}
+		}
+	})
+}
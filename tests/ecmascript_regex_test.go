@@ -0,0 +1,8 @@
+package tests
+
+import "testing"
+
+// TestEcmascriptRegexForTestSuite executes the ECMA-262 regular expression tests for Schema Test Suite.
+func TestEcmascriptRegexForTestSuite(t *testing.T) {
+	testJSONSchemaTestSuiteWithFilePath(t, "../testdata/JSON-Schema-Test-Suite/tests/draft2020-12/optional/ecmascript-regex.json")
+}
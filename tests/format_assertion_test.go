@@ -0,0 +1,42 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/kaptinlin/jsonschema"
+)
+
+// TestFormatAssertionForTestSuite executes the format-assertion vocabulary tests for Schema Test Suite.
+func TestFormatAssertionForTestSuite(t *testing.T) {
+	testJSONSchemaTestSuiteWithFilePath(t, "../testdata/JSON-Schema-Test-Suite/tests/draft2020-12/optional/format-assertion.json")
+}
+
+// TestUnknownFormatWithFormatAssertion checks that unknown formats are rejected by the meta-schemas
+// of the format-assertion suite, whatever the unknown format policy of the compiler.
+func TestUnknownFormatWithFormatAssertion(t *testing.T) {
+	server := startTestServer()
+	defer stopTestServer(server)
+
+	policies := []jsonschema.UnknownFormatPolicy{
+		jsonschema.UnknownFormatAnnotate,
+		jsonschema.UnknownFormatIgnore,
+		jsonschema.UnknownFormatAssert,
+	}
+
+	for _, metaSchema := range []string{"format-assertion-true.json", "format-assertion-false.json"} {
+		for _, policy := range policies {
+			compiler := jsonschema.NewCompiler().SetUnknownFormatPolicy(policy)
+			schema, err := compiler.Compile([]byte(`{
+				"$schema": "http://localhost:1234/draft2020-12/` + metaSchema + `",
+				"format": "no-such-format"
+			}`))
+			if err != nil {
+				t.Fatalf("Failed to compile schema: %v", err)
+			}
+
+			if schema.Validate("value").IsValid() {
+				t.Errorf("%s with policy %d: expected the unknown format to be rejected", metaSchema, policy)
+			}
+		}
+	}
+}
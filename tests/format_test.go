@@ -4,9 +4,7 @@ import "testing"
 
 // TestFormatForTestSuite executes the format validation tests for Schema Test Suite.
 func TestFormatForTestSuite(t *testing.T) {
-	testJSONSchemaTestSuiteWithFilePath(t, "../testdata/JSON-Schema-Test-Suite/tests/draft2020-12/format.json",
-		"idn-email format",
-		"idn-hostname format")
+	testJSONSchemaTestSuiteWithFilePath(t, "../testdata/JSON-Schema-Test-Suite/tests/draft2020-12/format.json")
 }
 
 func TestFormatDateTimeForTestSuite(t *testing.T) {
@@ -29,6 +27,14 @@ func TestFormatHostnameForTestSuite(t *testing.T) {
 	testJSONSchemaTestSuiteWithFilePath(t, "../testdata/JSON-Schema-Test-Suite/tests/draft2020-12/optional/format/hostname.json")
 }
 
+func TestFormatIdnEmailForTestSuite(t *testing.T) {
+	testJSONSchemaTestSuiteWithFilePath(t, "../testdata/JSON-Schema-Test-Suite/tests/draft2020-12/optional/format/idn-email.json")
+}
+
+func TestFormatIdnHostnameForTestSuite(t *testing.T) {
+	testJSONSchemaTestSuiteWithFilePath(t, "../testdata/JSON-Schema-Test-Suite/tests/draft2020-12/optional/format/idn-hostname.json")
+}
+
 func TestFormatIpv4ForTestSuite(t *testing.T) {
 	testJSONSchemaTestSuiteWithFilePath(t, "../testdata/JSON-Schema-Test-Suite/tests/draft2020-12/optional/format/ipv4.json")
 }
@@ -0,0 +1,99 @@
+package tests
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/goccy/go-json"
+
+	"github.com/kaptinlin/jsonschema"
+)
+
+// TestOutputForTestSuite checks the basic output format against the output tests of the JSON Schema Test Suite.
+func TestOutputForTestSuite(t *testing.T) {
+	dir := "../testdata/JSON-Schema-Test-Suite/output-tests/draft2020-12"
+
+	compiler := jsonschema.NewCompiler()
+	outputSchema, err := os.ReadFile(filepath.Join(dir, "output-schema.json"))
+	if err != nil {
+		t.Fatalf("Failed to read output schema: %s", err)
+	}
+	formatSchema, err := compiler.Compile(outputSchema)
+	if err != nil {
+		t.Fatalf("Failed to compile output schema: %s", err)
+	}
+
+	files, err := filepath.Glob(filepath.Join(dir, "content", "*.json"))
+	if err != nil || len(files) == 0 {
+		t.Fatalf("Failed to list output tests: %v", err)
+	}
+
+	type Test struct {
+		Description string                     `json:"description"`
+		Data        interface{}                `json:"data"`
+		Output      map[string]json.RawMessage `json:"output"`
+	}
+	type TestCase struct {
+		Description string          `json:"description"`
+		Schema      json.RawMessage `json:"schema"`
+		Tests       []Test          `json:"tests"`
+	}
+
+	for _, file := range files {
+		data, err := os.ReadFile(file) //nolint:gosec
+		if err != nil {
+			t.Fatalf("Failed to read test file: %s", err)
+		}
+		var testCases []TestCase
+		if err := json.Unmarshal(data, &testCases); err != nil {
+			t.Fatalf("Failed to unmarshal test cases: %s", err)
+		}
+
+		for _, tc := range testCases {
+			t.Run(filepath.Base(file)+"/"+tc.Description, func(t *testing.T) {
+				schema, err := compiler.Compile(tc.Schema)
+				if err != nil {
+					t.Fatalf("Failed to compile schema: %s", err)
+				}
+
+				for _, test := range tc.Tests {
+					t.Run(test.Description, func(t *testing.T) {
+						result := schema.Validate(test.Data)
+						outputs := map[string]interface{}{
+							"flag":     result.ToFlag(),
+							"basic":    result.ToBasic(),
+							"detailed": result.ToDetailed(),
+							"verbose":  result.ToVerbose(),
+						}
+
+						for format, output := range outputs {
+							encoded, err := json.Marshal(output)
+							if err != nil {
+								t.Fatalf("Failed to marshal %s output: %s", format, err)
+							}
+							var decoded interface{}
+							if err := json.Unmarshal(encoded, &decoded); err != nil {
+								t.Fatalf("Failed to unmarshal %s output: %s", format, err)
+							}
+
+							if outputResult := formatSchema.Validate(decoded); !outputResult.IsValid() {
+								t.Errorf("The %s output does not conform to the output schema: %s\n%v", format, encoded, outputResult.Err())
+							}
+
+							if expectation, ok := test.Output[format]; ok {
+								outputSchema, err := compiler.Compile(expectation)
+								if err != nil {
+									t.Fatalf("Failed to compile %s output schema: %s", format, err)
+								}
+								if outputResult := outputSchema.Validate(decoded); !outputResult.IsValid() {
+									t.Errorf("The %s output does not conform to its expectation: %s\n%v", format, encoded, outputResult.Err())
+								}
+							}
+						}
+					})
+				}
+			})
+		}
+	}
+}
@@ -2,9 +2,45 @@ package tests
 
 import (
 	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kaptinlin/jsonschema"
 )
 
 // TestUniqueItemsForTestSuite executes the uniqueItems validation tests for Schema Test Suite.
 func TestUniqueItemsForTestSuite(t *testing.T) {
 	testJSONSchemaTestSuiteWithFilePath(t, "../testdata/JSON-Schema-Test-Suite/tests/draft2020-12/uniqueItems.json")
 }
+
+// TestUniqueItemsJSONEquality checks that items are compared as JSON values, whatever the representation
+// of their numbers and the order of their members.
+func TestUniqueItemsJSONEquality(t *testing.T) {
+	schema, err := jsonschema.NewCompiler().Compile([]byte(`{"uniqueItems": true}`))
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		instance string
+		valid    bool
+	}{
+		{"integer and decimal", `[1, 1.0]`, false},
+		{"big numbers", `[12345678901234567890123, 1.2345678901234567890123e22]`, false},
+		{"close big numbers", `[12345678901234567890123, 12345678901234567890124]`, true},
+		{"member order", `[{"a": 1, "b": [2]}, {"b": [2.0], "a": 1}]`, false},
+		{"number and string", `[1, "1"]`, true},
+		{"false and zero", `[false, 0, null]`, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			result, err := schema.ValidateJSON([]byte(test.instance))
+			require.NoError(t, err)
+			assert.Equal(t, test.valid, result.IsValid())
+		})
+	}
+
+	result := schema.Validate([]interface{}{1, "a", 1.0, "a", 2, 1})
+	assert.Equal(t, "Found duplicates at the following index groups: (1, 3, 6), (2, 4)", result.Errors["uniqueItems"].Error())
+}
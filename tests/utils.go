@@ -1,6 +1,7 @@
 package tests
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"log"
@@ -66,9 +67,9 @@ func testJSONSchemaTestSuiteWithFilePath(t *testing.T, filePath string, exclusio
 	}
 
 	type Test struct {
-		Description string      `json:"description"`
-		Data        interface{} `json:"data"`
-		Valid       bool        `json:"valid"`
+		Description string          `json:"description"`
+		Data        json.RawMessage `json:"data"`
+		Valid       bool            `json:"valid"`
 	}
 	type TestCase struct {
 		Description string      `json:"description"`
@@ -108,6 +109,13 @@ func testJSONSchemaTestSuiteWithFilePath(t *testing.T, filePath string, exclusio
 				compiler.SetAssertFormat(true)
 			}
 
+			// Use the ECMA-262 dialect for the ecmascript-regex test cases, which also check
+			// the regex format.
+			if strings.Contains(filePath, "ecmascript-regex") {
+				compiler.SetRegexEngine(jsonschema.ECMAScriptRegexEngine{})
+				compiler.SetAssertFormat(true)
+			}
+
 			schema, err := compiler.Compile(schemaJSON)
 			if err != nil {
 				t.Fatalf("Failed to compile schema: %v", err)
@@ -121,21 +129,52 @@ func testJSONSchemaTestSuiteWithFilePath(t *testing.T, filePath string, exclusio
 					continue
 				}
 				t.Run(test.Description, func(t *testing.T) {
+					var data interface{}
+					if err := json.Unmarshal(test.Data, &data); err != nil {
+						t.Fatalf("Failed to unmarshal test data: %v", err)
+					}
+
 					// Evaluate the data against the schema.
-					result := schema.Validate(test.Data)
-
-					// Check if the test should pass or fail.
-					if test.Valid {
-						if !result.IsValid() {
-							t.Errorf("Expected data to be valid, but got error: %v", result.ToList())
-						}
-					} else {
-						if result.IsValid() {
-							t.Error("Expected data to be invalid, but got no error")
-						}
+					result := schema.Validate(data)
+					checkTestResult(t, test.Valid, result)
+
+					// Evaluate the data in fail-fast mode, whose validity must be the same.
+					result = schema.ValidateFast(data)
+					checkTestResult(t, test.Valid, result)
+
+					// Evaluate the raw data too, which keeps the exact value of its numbers.
+					result, err := schema.ValidateJSON(test.Data)
+					if err != nil {
+						t.Fatalf("Failed to validate raw test data: %v", err)
+					}
+					checkTestResult(t, test.Valid, result)
+
+					// Evaluate the raw data as a stream, whose validity must be the same.
+					valid, err := schema.ValidateStream(bytes.NewReader(test.Data), nil)
+					if err != nil {
+						t.Fatalf("Failed to validate test data stream: %v", err)
+					}
+					if valid != test.Valid {
+						t.Errorf("Expected streamed data validity to be %v, but got %v", test.Valid, valid)
 					}
 				})
 			}
 		})
 	}
 }
+
+// checkTestResult reports an error if the validity of result is not the expected one.
+func checkTestResult(t *testing.T, valid bool, result *jsonschema.EvaluationResult) {
+	t.Helper()
+
+	// Check if the test should pass or fail.
+	if valid {
+		if !result.IsValid() {
+			t.Errorf("Expected data to be valid, but got error: %v", result.ToList())
+		}
+	} else {
+		if result.IsValid() {
+			t.Error("Expected data to be invalid, but got no error")
+		}
+	}
+}
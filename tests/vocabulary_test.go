@@ -0,0 +1,8 @@
+package tests
+
+import "testing"
+
+// TestVocabularyForTestSuite executes the vocabulary validation tests for Schema Test Suite.
+func TestVocabularyForTestSuite(t *testing.T) {
+	testJSONSchemaTestSuiteWithFilePath(t, "../testdata/JSON-Schema-Test-Suite/tests/draft2020-12/vocabulary.json")
+}
@@ -1,7 +1,6 @@
 package jsonschema
 
 import (
-	"fmt"
 	"strconv"
 	"strings"
 )
@@ -19,7 +18,7 @@ import (
 // If an unevaluated array element does not conform, it returns a EvaluationError detailing the issue.
 //
 // Reference: https://json-schema.org/draft/2020-12/json-schema-core#name-unevaluateditems
-func evaluateUnevaluatedItems(schema *Schema, data interface{}, evaluatedProps map[string]bool, evaluatedItems map[int]bool, dynamicScope *DynamicScope) ([]*EvaluationResult, *EvaluationError) {
+func evaluateUnevaluatedItems(schema *Schema, data interface{}, result *EvaluationResult, evaluatedProps map[string]bool, evaluatedItems map[int]bool, dynamicScope *DynamicScope) ([]*EvaluationResult, *EvaluationError) {
 	items, ok := data.([]interface{})
 	if !ok {
 		return nil, nil // If data is not an array, then skip the array-specific validations.
@@ -27,27 +26,40 @@ func evaluateUnevaluatedItems(schema *Schema, data interface{}, evaluatedProps m
 
 	invalid_indexs := []string{}
 	results := []*EvaluationResult{}
+	applied := false
 
 	if schema.UnevaluatedItems != nil {
 		// Evaluate un-evaluated items against the schema.
 		for i, item := range items {
 			if _, evaluated := evaluatedItems[i]; !evaluated {
-				result, _, _ := schema.UnevaluatedItems.evaluate(item, dynamicScope)
-				if result != nil {
-					result.SetEvaluationPath(fmt.Sprintf("/unevaluatedItems/%d", i)).
-						SetSchemaLocation(schema.GetSchemaLocation(fmt.Sprintf("/unevaluatedItems/%d", i))).
-						SetInstanceLocation(fmt.Sprintf("/%d", i))
+				itemResult, _, _ := schema.UnevaluatedItems.evaluateAt(item, "/unevaluatedItems", strconv.Itoa(i), dynamicScope)
+				if itemResult != nil {
+					results = append(results, itemResult)
 
-					if !result.IsValid() {
+					if !itemResult.IsValid() {
 						invalid_indexs = append(invalid_indexs, strconv.Itoa(i))
 					}
 				}
 
 				evaluatedItems[i] = true
+				applied = true
+			}
+
+			if dynamicScope.isCanceled() {
+				// The items left were not checked, so the keyword cannot be reported as valid.
+				return results, newEvaluationCanceledError(dynamicScope.Context().Err())
+			}
+			if dynamicScope.stops(len(invalid_indexs) > 0) {
+				break // Stop at the item where the validation failed fast.
 			}
 		}
 	}
 
+	// The annotation is true as the subschema applied to every item that was not evaluated yet.
+	if applied && dynamicScope.annotates() {
+		result.AddAnnotation("unevaluatedItems", true)
+	}
+
 	if len(invalid_indexs) == 1 {
 		return results, NewEvaluationError("unevaluatedItems", "unevaluated_item_mismatch", "Item at index {index} does not match the unevaluatedItems schema", map[string]interface{}{
 			"index": invalid_indexs[0],
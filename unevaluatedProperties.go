@@ -14,13 +14,14 @@ import (
 // - The annotations influence the evaluation order, meaning all related properties and applicators must be processed first.
 //
 // Reference: https://json-schema.org/draft/2020-12/json-schema-core#name-unevaluatedproperties
-func evaluateUnevaluatedProperties(schema *Schema, data interface{}, evaluatedProps map[string]bool, evaluatedItems map[int]bool, dynamicScope *DynamicScope) ([]*EvaluationResult, *EvaluationError) {
+func evaluateUnevaluatedProperties(schema *Schema, data interface{}, result *EvaluationResult, evaluatedProps map[string]bool, evaluatedItems map[int]bool, dynamicScope *DynamicScope) ([]*EvaluationResult, *EvaluationError) {
 	if schema.UnevaluatedProperties == nil {
 		return nil, nil // If "unevaluatedProperties" is not defined, all properties are considered evaluated.
 	}
 
 	invalid_properties := []string{}
 	results := []*EvaluationResult{}
+	matched := []string{}
 
 	object, ok := data.(map[string]interface{})
 	if !ok {
@@ -29,23 +30,26 @@ func evaluateUnevaluatedProperties(schema *Schema, data interface{}, evaluatedPr
 
 	// Loop through all properties of the object to find unevaluated properties.
 	for propName, propValue := range object {
+		if dynamicScope.stops(len(invalid_properties) > 0) {
+			break
+		}
 		if _, evaluated := evaluatedProps[propName]; !evaluated {
 			// If property has not been evaluated, validate it against the "unevaluatedProperties" schema.
-			result, _, _ := schema.UnevaluatedProperties.evaluate(propValue, dynamicScope)
-			if result != nil {
-				result.SetEvaluationPath("/unevaluatedProperties").
-					SetSchemaLocation(schema.GetSchemaLocation("/unevaluatedProperties")).
-					SetInstanceLocation(fmt.Sprintf("/%s", propName))
-
-				results = append(results, result)
+			if dynamicScope.annotates() {
+				matched = append(matched, propName)
+			}
+			propResult, _, _ := schema.UnevaluatedProperties.evaluateAt(propValue, "/unevaluatedProperties", propName, dynamicScope)
+			if propResult != nil {
+				results = append(results, propResult)
 
-				if !result.IsValid() {
+				if !propResult.IsValid() {
 					invalid_properties = append(invalid_properties, propName)
 				}
 			}
 			evaluatedProps[propName] = true
 		}
 	}
+	annotateNames(result, "unevaluatedProperties", matched)
 
 	if len(invalid_properties) == 1 {
 		return results, NewEvaluationError("properties", "unevaluated_property_mismatch", "Property {property} does not match the unevaluatedProperties schema", map[string]interface{}{
@@ -3,8 +3,6 @@ package jsonschema
 import (
 	"fmt"
 	"strings"
-
-	"github.com/goccy/go-json"
 )
 
 // EvaluateUniqueItems checks if all elements in the array are unique when the "uniqueItems" property is set to true.
@@ -23,24 +21,30 @@ func evaluateUniqueItems(schema *Schema, data []interface{}) *EvaluationError {
 		return nil // If uniqueItems is not set to true, no validation is required.
 	}
 
-	// Using a map to track all indices of each item using serialized strings as keys.
-	seen := make(map[string][]int)
+	// Group the indices of equal items, numbers being compared by value. Items are only compared with
+	// the groups whose first item has the same hash.
+	var groups [][]int
+	byHash := make(map[uint64][]int, len(data))
 	for index, item := range data {
-		itemBytes, err := json.Marshal(item)
-		if err != nil {
-			// Handle serialization error
-			return NewEvaluationError("uniqueItems", "item_serialization_error", "Error serializing item at index {index}", map[string]interface{}{
-				"index": fmt.Sprint(index),
-			})
+		h := hashJSON(item)
+		found := false
+		for _, i := range byHash[h] {
+			if equalJSON(data[groups[i][0]], item) {
+				groups[i] = append(groups[i], index)
+				found = true
+				break
+			}
+		}
+		if !found {
+			byHash[h] = append(byHash[h], len(groups))
+			groups = append(groups, []int{index})
 		}
-		itemKey := string(itemBytes)
-		seen[itemKey] = append(seen[itemKey], index) // Append the current index to the list of indices for this item
 	}
 
 	// Prepare to report locations of all duplicate items
 	var duplicates []string
-	for _, indices := range seen {
-		if len(indices) > 1 { // Only consider keys with more than one index as duplicates
+	for _, indices := range groups {
+		if len(indices) > 1 { // Only consider groups with more than one index as duplicates
 			// Convert indices to 1-based for user-friendly output
 			for i := range indices {
 				indices[i] += 1
@@ -22,7 +22,11 @@ func replace(template string, params map[string]interface{}) string {
 }
 
 // mergeIntMaps merges two integer maps. The values in the second map overwrite the first where keys overlap.
+// Nothing is merged into a nil map.
 func mergeIntMaps(map1, map2 map[int]bool) map[int]bool {
+	if map1 == nil {
+		return nil
+	}
 	for key, value := range map2 {
 		map1[key] = value
 	}
@@ -30,7 +34,11 @@ func mergeIntMaps(map1, map2 map[int]bool) map[int]bool {
 }
 
 // mergeStringMaps merges two string maps. The values in the second map overwrite the first where keys overlap.
+// Nothing is merged into a nil map.
 func mergeStringMaps(map1, map2 map[string]bool) map[string]bool {
+	if map1 == nil {
+		return nil
+	}
 	for key, value := range map2 {
 		map1[key] = value
 	}
@@ -150,7 +158,162 @@ func splitRef(ref string) (baseURI string, anchor string) {
 	return ref, ""
 }
 
+// escapeJSONPointerSegment escapes a reference token for use in a JSON Pointer, see RFC 6901.
+func escapeJSONPointerSegment(segment string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(segment, "~", "~0"), "/", "~1")
+}
+
 // isJSONPointer checks if a string is a JSON Pointer.
 func isJSONPointer(s string) bool {
 	return strings.HasPrefix(s, "/")
 }
+
+// equalJSON reports whether two JSON values are equal. Numbers are compared by value, so 1, 1.0 and
+// json.Number("1") are equal whatever their Go representation, and objects regardless of the order of
+// their members.
+func equalJSON(a, b interface{}) bool {
+	switch a := a.(type) {
+	case nil:
+		return b == nil
+	case bool:
+		b, ok := b.(bool)
+		return ok && a == b
+	case string:
+		b, ok := b.(string)
+		return ok && a == b
+	case map[string]interface{}:
+		b, ok := b.(map[string]interface{})
+		if !ok || len(a) != len(b) {
+			return false
+		}
+		for key, value := range a {
+			other, ok := b[key]
+			if !ok || !equalJSON(value, other) {
+				return false
+			}
+		}
+		return true
+	case []interface{}:
+		b, ok := b.([]interface{})
+		if !ok || len(a) != len(b) {
+			return false
+		}
+		for i := range a {
+			if !equalJSON(a[i], b[i]) {
+				return false
+			}
+		}
+		return true
+	}
+
+	if isNumber(a) || isNumber(b) {
+		if !isNumber(a) || !isNumber(b) {
+			return false
+		}
+		x, y := NewRat(a), NewRat(b)
+		return x != nil && y != nil && x.Cmp(y.Rat) == 0
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+// isNumber tells whether v is a JSON number.
+func isNumber(v interface{}) bool {
+	switch v.(type) {
+	case json.Number, float32, float64, int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		return true
+	}
+	return false
+}
+
+// FNV-1a parameters of the hashes of JSON values.
+const (
+	fnvOffset uint64 = 14695981039346656037
+	fnvPrime  uint64 = 1099511628211
+)
+
+// hashJSON returns a hash of a JSON value consistent with equalJSON: equal values have the same hash,
+// whatever the Go representation of their numbers and the order of their members.
+func hashJSON(v interface{}) uint64 {
+	switch v := v.(type) {
+	case nil:
+		return hashUint(fnvOffset, 'n')
+	case bool:
+		if v {
+			return hashUint(fnvOffset, 't')
+		}
+		return hashUint(fnvOffset, 'f')
+	case string:
+		return hashString(hashUint(fnvOffset, 's'), v)
+	case map[string]interface{}:
+		// Members are hashed on their own and summed, so that their order does not matter.
+		var sum uint64
+		for key, value := range v {
+			sum += hashUint(hashString(fnvOffset, key), hashJSON(value))
+		}
+		return hashUint(hashUint(fnvOffset, 'o'), sum)
+	case []interface{}:
+		h := hashUint(fnvOffset, 'a')
+		for _, item := range v {
+			h = hashUint(h, hashJSON(item))
+		}
+		return h
+	}
+
+	if isNumber(v) {
+		if r := NewRat(v); r != nil {
+			// Rationals are kept normalized, so equal numbers have the same numerator and denominator.
+			h := hashUint(fnvOffset, 'd')
+			if r.IsInt() && r.Num().IsInt64() {
+				return hashUint(h, uint64(r.Num().Int64()))
+			}
+			h = hashUint(h, uint64(r.Sign()+1))
+			h = hashString(h, string(r.Num().Bytes()))
+			return hashString(h, string(r.Denom().Bytes()))
+		}
+	}
+	// Other values are left to equalJSON.
+	return fnvOffset
+}
+
+// hashUint adds the bytes of x to the FNV-1a hash h.
+func hashUint(h, x uint64) uint64 {
+	for i := 0; i < 8; i++ {
+		h ^= x & 0xff
+		h *= fnvPrime
+		x >>= 8
+	}
+	return h
+}
+
+// hashString adds the length and the bytes of s to the FNV-1a hash h.
+func hashString(h uint64, s string) uint64 {
+	h = hashUint(h, uint64(len(s)))
+	for i := 0; i < len(s); i++ {
+		h ^= uint64(s[i])
+		h *= fnvPrime
+	}
+	return h
+}
+
+// jsonSet is a set of JSON values, found by their hashJSON and compared with equalJSON.
+type jsonSet map[uint64][]interface{}
+
+// newJSONSet returns the set of the given values.
+func newJSONSet(values []interface{}) jsonSet {
+	set := make(jsonSet, len(values))
+	for _, value := range values {
+		h := hashJSON(value)
+		set[h] = append(set[h], value)
+	}
+	return set
+}
+
+// contains reports whether the set holds a value equal to v.
+func (s jsonSet) contains(v interface{}) bool {
+	for _, value := range s[hashJSON(v)] {
+		if equalJSON(value, v) {
+			return true
+		}
+	}
+	return false
+}
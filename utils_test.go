@@ -1,6 +1,7 @@
 package jsonschema
 
 import (
+	"encoding/json"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -155,3 +156,51 @@ func TestIsJSONPointer(t *testing.T) {
 		})
 	}
 }
+
+func TestEqualJSON(t *testing.T) {
+	tests := []struct {
+		name     string
+		a, b     interface{}
+		expected bool
+	}{
+		{"integer and float", 1, 1.0, true},
+		{"float and json.Number", 1.0, json.Number("1.0"), true},
+		{"json.Number exponent", json.Number("1e2"), 100, true},
+		{"big integers", json.Number("12345678901234567890123"), json.Number("1.2345678901234567890123e22"), true},
+		{"different big integers", json.Number("12345678901234567890123"), json.Number("12345678901234567890124"), false},
+		{"decimals", 0.1, json.Number("0.1"), true},
+		{"different numbers", 1, 1.5, false},
+		{"number and string", 1, "1", false},
+		{"false and zero", false, 0, false},
+		{"null and false", nil, false, false},
+		{"object member order", map[string]interface{}{"a": 1, "b": []interface{}{1.0, "x"}}, map[string]interface{}{"b": []interface{}{json.Number("1"), "x"}, "a": 1.0}, true},
+		{"object with another member", map[string]interface{}{"a": 1}, map[string]interface{}{"b": 1}, false},
+		{"array order", []interface{}{1, 2}, []interface{}{2, 1}, false},
+		{"nested null", []interface{}{nil}, []interface{}{nil}, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, equalJSON(test.a, test.b))
+			assert.Equal(t, test.expected, equalJSON(test.b, test.a))
+			if test.expected {
+				assert.Equal(t, hashJSON(test.a), hashJSON(test.b), "Equal values must have the same hash")
+			}
+		})
+	}
+}
+
+func TestJSONSet(t *testing.T) {
+	set := newJSONSet([]interface{}{
+		1.5, "1", nil, map[string]interface{}{"a": 1, "b": 2}, json.Number("12345678901234567890123"),
+	})
+
+	assert.True(t, set.contains(json.Number("1.50")))
+	assert.True(t, set.contains("1"))
+	assert.True(t, set.contains(nil))
+	assert.True(t, set.contains(map[string]interface{}{"b": 2.0, "a": json.Number("1")}))
+	assert.True(t, set.contains(json.Number("1.2345678901234567890123e22")))
+	assert.False(t, set.contains(1))
+	assert.False(t, set.contains(false))
+	assert.False(t, set.contains(map[string]interface{}{"a": 1}))
+}
@@ -1,309 +1,158 @@
 package jsonschema
 
 import (
-	"dario.cat/mergo"
+	"context"
 	"encoding/json"
 	"io"
-	"strings"
+
+	"dario.cat/mergo"
 )
 
 // Evaluate checks if the given instance conforms to the schema.
 func (s *Schema) Validate(instance interface{}) *EvaluationResult {
-	dynamicScope := NewDynamicScope()
-	result, _, _ := s.evaluate(instance, dynamicScope)
-
-	return result
+	return s.ValidateContext(context.Background(), instance)
 }
 
-func (s *Schema) evaluate(instance interface{}, dynamicScope *DynamicScope) (*EvaluationResult, map[string]bool, map[int]bool) {
-	dynamicScope.Push(s)
-	result := NewEvaluationResult(s)
-
-	evaluatedProps := make(map[string]bool)
-	evaluatedItems := make(map[int]bool)
-
-	if s.Boolean != nil {
-		// Check if the schema is a boolean
-		if err := s.evaluateBoolean(instance, evaluatedProps, evaluatedItems); err != nil {
-			result.AddError(err)
-		}
-	} else {
-		// Check basicURI if present
-		// if s.ID != "" {
-		// 	if err := evaluateID(s); err != nil {
-		// 		errs.AddCause(err)
-		// 	}
-		// }
-
-		// Compile patterns for PatternProperties if not already compiled
-		if s.PatternProperties != nil {
-			s.compilePatterns()
-		}
-
-		if len(s.Ref) > 0 {
-			//parsedRef, _ := urlParse(s.Ref)
-			if strings.HasPrefix(s.Ref, "tf://") {
-				s.ResolvedRef = nil
-				var err error
-
-				if getDataType(instance) != "object" {
-					//panic("instance is not an object")
-					result.AddError(
-						NewEvaluationError("@schema", "type_not_found", "Cant find the reference type schema"),
-					)
-				} else {
-					value, ok := instance.(map[string]interface{})
-					if !ok {
-						result.AddError(
-							NewEvaluationError("@schema", "type_not_found", "Cant find the reference type schema"),
-						)
-					} else if _, ok := value["@schema"]; !ok {
-						result.AddError(
-							NewEvaluationError("@schema", "type_not_found", "Cant find the reference type schema"),
-						)
-					} else {
-						s.ResolvedRef, err = s.resolveRef(value["@schema"].(string))
-						if err != nil {
-							result.AddError(
-								NewEvaluationError("@schema", "schema_cant_reach", "Cant reach the reference @schema"),
-							)
-						}
-					}
-				}
-			}
-		}
-
-		// Check if there is a resolved reference and validate against it if present
-		if s.ResolvedRef != nil {
-			refResult, props, items := s.ResolvedRef.evaluate(instance, dynamicScope)
-
-			if refResult != nil {
-				result.AddDetail(refResult)
-
-				if !refResult.IsValid() {
-					result.AddError(
-						NewEvaluationError("$ref", "ref_mismatch", "Value does not match the reference schema"),
-					)
-				}
-			}
-
-			mergeStringMaps(evaluatedProps, props)
-			mergeIntMaps(evaluatedItems, items)
-		}
-
-		if s.ResolvedDynamicRef != nil {
-			anchorSchema := s.ResolvedDynamicRef
-			_, anchor := splitRef(s.DynamicRef)
-			if !isJSONPointer(anchor) {
-				dynamicAnchor := s.ResolvedDynamicRef.DynamicAnchor
-				if dynamicAnchor != "" {
-					if schema := dynamicScope.LookupDynamicAnchor(dynamicAnchor); schema != nil {
-						anchorSchema = schema
-					}
-				}
-			}
-
-			dynamicRefResult, props, items := anchorSchema.evaluate(instance, dynamicScope)
-			if dynamicRefResult != nil {
-				result.AddDetail(dynamicRefResult)
-
-				if !dynamicRefResult.IsValid() {
-					result.AddError(
-						NewEvaluationError("$dynamicRef", "dynamic_ref_mismatch", "Value does not match the dynamic reference schema"),
-					)
-				}
-			}
-
-			mergeStringMaps(evaluatedProps, props)
-			mergeIntMaps(evaluatedItems, items)
-		}
-
-		// Validation keywords for any instance type
-		if s.Type != nil {
-			if err := evaluateType(s, instance); err != nil {
-				result.AddError(err)
-			}
-		}
-
-		if s.Enum != nil {
-			if err := evaluateEnum(s, instance); err != nil {
-				result.AddError(err)
-			}
-		}
-
-		if s.Const != nil {
-			if err := evaluateConst(s, instance); err != nil {
-				result.AddError(err)
-			}
-		}
+// ValidateContext checks if the given instance conforms to the schema, stopping once ctx is canceled
+// or its deadline expires. Loaders used for tf:// "@schema" lookups and "@parent" fetches receive ctx.
+// When evaluation is cut off, the result is invalid and the subschema results where it stopped
+// carry an "evaluation_canceled" error at their evaluation path and instance location.
+//
+// Besides the generic representation of JSON values produced by json.Unmarshal, the instance can
+// be any Go value encodable as JSON, such as a struct honoring its json tags, a typed map or slice,
+// a pointer, a time.Time or a json.RawMessage. A value without a JSON representation makes the
+// result invalid with an "invalid_instance" error.
+func (s *Schema) ValidateContext(ctx context.Context, instance interface{}) *EvaluationResult {
+	normalized, err := normalizeInstance(instance)
+	if err != nil {
+		return NewEvaluationResult(s).SetSchemaLocation(s.GetSchemaLocation("")).AddError(
+			NewEvaluationError("instance", "invalid_instance", "Value cannot be validated: {error}", map[string]interface{}{
+				"error": err.Error(),
+			}),
+		)
+	}
 
-		// Validation keywords for applying subschemas with logical operations
-		if s.AllOf != nil {
-			allOfResults, allOfError := evaluateAllOf(s, instance, evaluatedProps, evaluatedItems, dynamicScope)
-			for _, allOfResult := range allOfResults {
-				result.AddDetail(allOfResult)
-			}
-			if allOfError != nil {
-				result.AddError(allOfError)
-			}
-		}
+	dynamicScope := NewDynamicScope()
+	dynamicScope.ctx = ctx
+	dynamicScope.failFast, _ = ctx.Value(failFastKey{}).(bool)
+	dynamicScope.direction, _ = ctx.Value(directionKey{}).(Direction)
+	result, _, _ := s.evaluate(normalized, dynamicScope)
 
-		if s.AnyOf != nil {
-			anyOfResults, anyOfError := evaluateAnyOf(s, instance, evaluatedProps, evaluatedItems, dynamicScope)
-			for _, anyOfResult := range anyOfResults {
-				result.AddDetail(anyOfResult)
-			}
-			if anyOfError != nil {
-				result.AddError(anyOfError)
-			}
-		}
+	return result.locate()
+}
 
-		if s.OneOf != nil {
-			oneOfResults, oneOfError := evaluateOneOf(s, instance, evaluatedProps, evaluatedItems, dynamicScope)
-			for _, oneOfResult := range oneOfResults {
-				result.AddDetail(oneOfResult)
-			}
-			if oneOfError != nil {
-				result.AddError(oneOfError)
-			}
-		}
+// ValidateFast checks if the given instance conforms to the schema in fail-fast mode, see WithFailFast.
+func (s *Schema) ValidateFast(instance interface{}) *EvaluationResult {
+	return s.ValidateContext(WithFailFast(context.Background()), instance)
+}
 
-		if s.Not != nil {
-			notResult, notError := evaluateNot(s, instance, evaluatedProps, evaluatedItems, dynamicScope)
-			if notResult != nil {
-				result.AddDetail(notResult)
-			}
-			if notError != nil {
-				result.AddError(notError)
-			}
-		}
+// failFastKey is the context key of the fail-fast mode.
+type failFastKey struct{}
 
-		// Validation keywords for applying subschemas with conditional logic
-		if s.If != nil || s.Then != nil || s.Else != nil {
-			conditionalResults, conditionalError := evaluateConditional(s, instance, evaluatedProps, evaluatedItems, dynamicScope)
-			for _, conditionalResult := range conditionalResults {
-				result.AddDetail(conditionalResult)
-			}
-			if conditionalError != nil {
-				result.AddError(conditionalError)
-			}
-		}
+// WithFailFast returns a copy of ctx which makes ValidateContext run in fail-fast mode: each schema stops
+// at the first keyword that fails, so that the result reports the first failure found rather than all of
+// them, no annotations are collected, and the results of the subschemas that passed are dropped. The
+// validity of the instance is the same as in the default mode.
+func WithFailFast(ctx context.Context) context.Context {
+	return context.WithValue(ctx, failFastKey{}, true)
+}
 
-		// Validation keywords for applying subschemas to arrays
-		if s.PrefixItems != nil && len(s.PrefixItems) > 0 ||
-			s.Items != nil ||
-			s.Contains != nil ||
-			s.MaxContains != nil ||
-			s.MinContains != nil ||
-			s.MaxItems != nil ||
-			s.MinItems != nil ||
-			s.UniqueItems != nil {
-			arrayResults, arrayErrors := evaluateArray(s, instance, evaluatedProps, evaluatedItems, dynamicScope)
-			for _, arrayResult := range arrayResults {
-				result.AddDetail(arrayResult)
-			}
-			for _, arrayError := range arrayErrors {
-				result.AddError(arrayError)
-			}
+// failedDetails returns the failing results among details, reusing its storage.
+func failedDetails(details []*EvaluationResult) []*EvaluationResult {
+	failed := details[:0]
+	for _, detail := range details {
+		if detail != nil && !detail.Valid {
+			failed = append(failed, detail)
 		}
+	}
+	return failed
+}
 
-		// Validation Keywords for Numeric Instances (number and integer)
-		if s.MultipleOf != nil || s.Maximum != nil || s.ExclusiveMaximum != nil || s.Minimum != nil || s.ExclusiveMinimum != nil {
-			numericErrors := evaluateNumeric(s, instance)
-			for _, numericError := range numericErrors {
-				result.AddError(numericError)
-			}
-		}
+func (s *Schema) evaluate(instance interface{}, dynamicScope *DynamicScope) (*EvaluationResult, map[string]bool, map[int]bool) {
+	plan := s.plan
+	if plan == nil {
+		// The schema was not compiled, such as a schema built in code.
+		plan = s.compilePlan()
+	}
 
-		// Validation Keywords for Strings
-		if s.MaxLength != nil || s.MinLength != nil || s.Pattern != nil {
-			stringErrors := evaluateString(s, instance)
-			for _, stringError := range stringErrors {
-				result.AddError(stringError)
+	dynamicScope.Push(s)
+	result := &EvaluationResult{
+		schema:           s,
+		Valid:            true,
+		SchemaLocation:   plan.location,
+		evaluationPath:   dynamicScope.evaluationPath,
+		instanceLocation: dynamicScope.instanceLocation,
+		pending:          true,
+	}
+	if dynamicScope.annotates() {
+		result.CollectAnnotations()
+	}
+
+	// The properties and items evaluated are only tracked for the unevaluated* keywords of s, or of the
+	// schemas applying s to the same instance.
+	tracking := dynamicScope.tracking
+	dynamicScope.tracking = tracking || plan.tracksEvaluated
+	var evaluatedProps map[string]bool
+	var evaluatedItems map[int]bool
+	if dynamicScope.tracking {
+		evaluatedProps = make(map[string]bool)
+		evaluatedItems = make(map[int]bool)
+	}
+
+	if err := dynamicScope.Context().Err(); err != nil {
+		result.AddError(newEvaluationCanceledError(err))
+	} else {
+		for _, evaluate := range plan.evaluators {
+			evaluate(s, instance, result, evaluatedProps, evaluatedItems, dynamicScope)
+			if dynamicScope.stops(!result.Valid) {
+				break
 			}
 		}
+	}
 
-		if s.Format != nil {
-			formatError := evaluateFormat(s, instance)
-			if formatError != nil {
-				result.AddError(formatError)
-			}
-		}
+	if dynamicScope.failFast {
+		result.Details = failedDetails(result.Details)
+	} else if !result.Valid {
+		// Annotations of a schema that failed are dropped, see AnnotationsAt.
+		result.Annotations = nil
+	}
 
-		// Validation Keywords for Objects
-		if s.Properties != nil ||
-			s.PatternProperties != nil ||
-			s.AdditionalProperties != nil ||
-			s.PropertyNames != nil ||
-			s.MaxProperties != nil ||
-			s.MinProperties != nil ||
-			len(s.Required) > 0 ||
-			len(s.DependentRequired) > 0 {
-			objectResults, objectErrors := evaluateObject(s, instance, evaluatedProps, evaluatedItems, dynamicScope)
-			for _, objectResult := range objectResults {
-				result.AddDetail(objectResult)
-			}
-			for _, objectError := range objectErrors {
-				result.AddError(objectError)
-			}
-		}
+	dynamicScope.tracking = tracking
+	// Pop the schema from the dynamic scope
+	dynamicScope.Pop()
 
-		// Validation dependentSchemas
-		if s.DependentSchemas != nil {
-			dependentSchemasResults, dependentSchemasError := evaluateDependentSchemas(s, instance, evaluatedProps, evaluatedItems, dynamicScope)
-			for _, dependentSchemasResult := range dependentSchemasResults {
-				result.AddDetail(dependentSchemasResult)
-			}
-			if dependentSchemasError != nil {
-				result.AddError(dependentSchemasError)
-			}
-		}
+	return result, evaluatedProps, evaluatedItems
+}
 
-		// Validation unevaluatedProperties
-		if s.UnevaluatedProperties != nil {
-			unevaluatedPropertiesResults, unevaluatedPropertiesError := evaluateUnevaluatedProperties(s, instance, evaluatedProps, evaluatedItems, dynamicScope)
-			for _, unevaluatedPropertiesResult := range unevaluatedPropertiesResults {
-				result.AddDetail(unevaluatedPropertiesResult)
-			}
-			if unevaluatedPropertiesError != nil {
-				result.AddError(unevaluatedPropertiesError)
-			}
-		}
+// evaluateIn evaluates the current instance against s, the subschema found at the escaped keyword path
+// of the current schema, keeping track of its evaluation path in the dynamic scope.
+func (s *Schema) evaluateIn(instance interface{}, keywordPath string, dynamicScope *DynamicScope) (*EvaluationResult, map[string]bool, map[int]bool) {
+	dynamicScope.enterKeyword(keywordPath)
+	defer dynamicScope.leaveKeyword()
 
-		// Validation UnevaluatedItems
-		if s.UnevaluatedItems != nil {
-			unevaluatedItemsResults, unevaluatedItemsError := evaluateUnevaluatedItems(s, instance, evaluatedProps, evaluatedItems, dynamicScope)
-			for _, unevaluatedItemsResult := range unevaluatedItemsResults {
-				result.AddDetail(unevaluatedItemsResult)
-			}
-			if unevaluatedItemsError != nil {
-				result.AddError(unevaluatedItemsError)
-			}
-		}
+	return s.evaluate(instance, dynamicScope)
+}
 
-		// Validation Keywords for String-Encoded Data
-		if s.ContentEncoding != nil || s.ContentMediaType != nil || s.ContentSchema != nil {
-			contentResult, contentError := evaluateContent(s, instance, evaluatedProps, evaluatedItems, dynamicScope)
-			if contentError != nil {
-				result.AddDetail(contentResult)
-			}
-			if contentError != nil {
-				result.AddError(contentError)
-			}
-		}
+// evaluateAt evaluates a child of the current instance, the member or item named by segment, against s,
+// the subschema found at the escaped keyword path of the current schema, keeping track of their
+// locations in the dynamic scope.
+func (s *Schema) evaluateAt(instance interface{}, keywordPath string, segment string, dynamicScope *DynamicScope) (*EvaluationResult, map[string]bool, map[int]bool) {
+	dynamicScope.enterInstance(segment)
+	defer dynamicScope.leaveInstance()
 
-		//if len(s.XTFAcceptedObjects) > 0 {
-		//	idErrors := evaluateId(s, s.compiler, instance)
-		//	for _, idError := range idErrors {
-		//		result.AddError(idError)
-		//	}
-		//}
-	}
+	// The properties and items of a child are not those of the instance, so they need no tracking.
+	tracking := dynamicScope.tracking
+	dynamicScope.tracking = false
+	defer func() { dynamicScope.tracking = tracking }()
 
-	// Pop the schema from the dynamic scope
-	dynamicScope.Pop()
+	return s.evaluateIn(instance, keywordPath, dynamicScope)
+}
 
-	return result, evaluatedProps, evaluatedItems
+// newEvaluationCanceledError reports that the validation stopped because its context is done.
+func newEvaluationCanceledError(err error) *EvaluationError {
+	return NewEvaluationError("context", "evaluation_canceled", "Evaluation was canceled: {error}", map[string]interface{}{
+		"error": err.Error(),
+	})
 }
 
 func (s *Schema) evaluateBoolean(instance interface{}, evaluatedProps map[string]bool, evaluatedItems map[int]bool) *EvaluationError {
@@ -312,6 +161,9 @@ func (s *Schema) evaluateBoolean(instance interface{}, evaluatedProps map[string
 	}
 
 	if *s.Boolean {
+		if evaluatedProps == nil {
+			return nil // The evaluated properties and items are not tracked.
+		}
 		switch v := instance.(type) {
 		case map[string]interface{}:
 			for key := range v {
@@ -328,68 +180,44 @@ func (s *Schema) evaluateBoolean(instance interface{}, evaluatedProps map[string
 	}
 }
 
-func evaluateId(schema *Schema, compiler *Compiler, data interface{}) []*EvaluationError {
-	uri, ok := data.(string)
+// mergeParent returns a copy of the object referenced by the "@parent" member of object, with the
+// members of object taking precedence. The object, shared by the goroutines validating it, is read
+// but never modified.
+func mergeParent(schema *Schema, object map[string]interface{}, dynamicScope *DynamicScope) (map[string]interface{}, *EvaluationError) {
+	cantReach := NewEvaluationError("@parent", "parent_cant_reach", "Cant reach the referenced object")
+
+	url, ok := object["@parent"].(string)
 	if !ok {
-		// If data is not a string, then skip the string-specific validations.
-		return nil
+		return nil, cantReach
 	}
-
-	errors := []*EvaluationError{}
-
-	loader, ok := compiler.Loaders[getURLScheme(uri)]
+	loader, ok := schema.compiler.getLoader(getURLScheme(url))
 	if !ok {
-		return append(errors, NewEvaluationError("@id", "id_cant_reach", "Cant reach the referenced object"))
+		return nil, cantReach
 	}
 
-	body, err := loader(uri)
+	body, err := loader(dynamicScope.Context(), url+"?tenantOverride=false")
 	if err != nil {
-		return append(errors, NewEvaluationError("@id", "id_cant_reach", "Cant reach the referenced object"))
+		return nil, cantReach
 	}
 	defer body.Close() //nolint:errcheck
 
 	objectData, err := io.ReadAll(body)
 	if err != nil {
-		return append(errors, NewEvaluationError("@id", "id_cant_reach", "Cant reach the referenced object"))
+		return nil, cantReach
 	}
-	type (
-		WithSchema struct {
-			Schema string `json:"@schema"`
-		}
-	)
-	withSchemaInstance := WithSchema{}
-	err = json.Unmarshal(objectData, &withSchemaInstance)
-	if err != nil {
-		return append(errors, NewEvaluationError("@id", "id_without_schema", "Referenced object does not contains @schema"))
-	}
-
-	//for targetObjectSchema := range schema.XTFAcceptedObjects {
-	//	if withSchemaInstance.Schema == targetObjectSchema {
-	//		// full match including a possible version
-	//		return nil
-	//	}
-	//	if strings.HasPrefix(withSchemaInstance.Schema, targetObjectSchema) {
-	//		// match without version
-	//		return nil
-	//	}
-	//}
-
-	//parsedType, err := urlParse(withSchemaInstance.Schema)
-	//if err != nil {
-	//	return append(errors, NewEvaluationError("@id", "id_invalid_schema", "Referenced object does not contains valid @schema"))
-	//}
 
-	//for targetObjectSchema := range schema.XTFAcceptedObjects {
-	//	if strings.HasPrefix(parsedType.Path, targetObjectSchema) {
-	//		return nil
-	//	}
-	//}
-
-	return append(errors, NewEvaluationError("@id", "id_forbidden_schema", "Referenced object does not contains permitted @schema"))
+	parentObject := map[string]interface{}{}
+	if err := json.Unmarshal(objectData, &parentObject); err != nil {
+		return nil, cantReach
+	}
+	if err := mergo.Merge(&parentObject, object, mergo.WithOverride); err != nil {
+		return nil, cantReach
+	}
+	return parentObject, nil
 }
 
 // evaluateObject groups the validation of all object-specific keywords.
-func evaluateObject(schema *Schema, data interface{}, evaluatedProps map[string]bool, evaluatedItems map[int]bool, dynamicScope *DynamicScope) (results []*EvaluationResult, errors []*EvaluationError) {
+func evaluateObject(schema *Schema, data interface{}, result *EvaluationResult, evaluatedProps map[string]bool, evaluatedItems map[int]bool, dynamicScope *DynamicScope) (results []*EvaluationResult, errors []*EvaluationError) {
 	object, ok := data.(map[string]interface{})
 	if !ok {
 		// If data is not an object, then skip the object-specific validations.
@@ -400,39 +228,20 @@ func evaluateObject(schema *Schema, data interface{}, evaluatedProps map[string]
 	errors = []*EvaluationError{}
 
 	if object["@parent"] != nil {
-		url := object["@parent"].(string)
-		loader, ok := schema.compiler.Loaders[getURLScheme(url)]
-		if !ok {
-			errors = append(errors, NewEvaluationError("@parent", "parent_cant_reach", "Cant reach the referenced object"))
-			return
-		}
-
-		body, err := loader(url + "?tenantOverride=false")
+		parentObject, err := mergeParent(schema, object, dynamicScope)
 		if err != nil {
-			errors = append(errors, NewEvaluationError("@parent", "parent_cant_reach", "Cant reach the referenced object"))
-			return
-		}
-		defer body.Close() //nolint:errcheck
-
-		objectData, err := io.ReadAll(body)
-		if err != nil {
-			errors = append(errors, NewEvaluationError("@parent", "parent_cant_reach", "Cant reach the referenced object"))
+			errors = append(errors, err)
 			return
 		}
-
-		parentObject := map[string]interface{}{}
-		err = json.Unmarshal(objectData, &object)
-
-		err = mergo.Merge(&parentObject, object, mergo.WithOverride)
-		if err != nil {
-			panic(err)
-		}
 		object = parentObject
 	}
 
+	applicator := schema.hasVocabulary(VocabularyApplicator)
+	validation := schema.hasVocabulary(VocabularyValidation)
+
 	// Validation Keywords for applying subschemas to Objects
-	if schema.Properties != nil {
-		propertiesResults, propertiesError := evaluateProperties(schema, object, evaluatedProps, evaluatedItems, dynamicScope)
+	if applicator && schema.Properties != nil {
+		propertiesResults, propertiesError := evaluateProperties(schema, object, result, evaluatedProps, evaluatedItems, dynamicScope)
 
 		if propertiesResults != nil {
 			results = append(results, propertiesResults...)
@@ -442,8 +251,8 @@ func evaluateObject(schema *Schema, data interface{}, evaluatedProps map[string]
 		}
 	}
 
-	if schema.PatternProperties != nil {
-		patternPropertiesResults, patternPropertiesError := evaluatePatternProperties(schema, object, evaluatedProps, evaluatedItems, dynamicScope)
+	if !dynamicScope.stops(len(errors) > 0) && applicator && schema.PatternProperties != nil {
+		patternPropertiesResults, patternPropertiesError := evaluatePatternProperties(schema, object, result, evaluatedProps, evaluatedItems, dynamicScope)
 
 		if patternPropertiesResults != nil {
 			results = append(results, patternPropertiesResults...)
@@ -453,8 +262,8 @@ func evaluateObject(schema *Schema, data interface{}, evaluatedProps map[string]
 		}
 	}
 
-	if schema.AdditionalProperties != nil {
-		additionalPropertiesResults, additionalPropertiesError := evaluateAdditionalProperties(schema, object, evaluatedProps, evaluatedItems, dynamicScope)
+	if !dynamicScope.stops(len(errors) > 0) && applicator && schema.AdditionalProperties != nil {
+		additionalPropertiesResults, additionalPropertiesError := evaluateAdditionalProperties(schema, object, result, evaluatedProps, evaluatedItems, dynamicScope)
 
 		if additionalPropertiesResults != nil {
 			results = append(results, additionalPropertiesResults...)
@@ -464,7 +273,7 @@ func evaluateObject(schema *Schema, data interface{}, evaluatedProps map[string]
 		}
 	}
 
-	if schema.PropertyNames != nil {
+	if !dynamicScope.stops(len(errors) > 0) && applicator && schema.PropertyNames != nil {
 		propertyNamesResults, propertyNamesError := evaluatePropertyNames(schema, object, evaluatedProps, evaluatedItems, dynamicScope)
 
 		if propertyNamesResults != nil {
@@ -476,26 +285,26 @@ func evaluateObject(schema *Schema, data interface{}, evaluatedProps map[string]
 	}
 
 	// Validation Keywords for Objects
-	if schema.MaxProperties != nil {
+	if !dynamicScope.stops(len(errors) > 0) && validation && schema.MaxProperties != nil {
 		if err := evaluateMaxProperties(schema, object); err != nil {
 			errors = append(errors, err)
 		}
 	}
 
-	if schema.MinProperties != nil {
+	if !dynamicScope.stops(len(errors) > 0) && validation && schema.MinProperties != nil {
 		if err := evaluateMinProperties(schema, object); err != nil {
 			errors = append(errors, err)
 		}
 	}
 
-	if len(schema.Required) > 0 {
-		requiredError := evaluateRequired(schema, object)
+	if !dynamicScope.stops(len(errors) > 0) && validation && len(schema.Required) > 0 {
+		requiredError := evaluateRequired(schema, object, dynamicScope.direction)
 		if requiredError != nil {
 			errors = append(errors, requiredError)
 		}
 	}
 
-	if len(schema.DependentRequired) > 0 {
+	if !dynamicScope.stops(len(errors) > 0) && validation && len(schema.DependentRequired) > 0 {
 		if err := evaluateDependentRequired(schema, object); err != nil {
 			errors = append(errors, err)
 		}
@@ -600,7 +409,7 @@ func evaluateString(schema *Schema, data interface{}) []*EvaluationError {
 }
 
 // validateArray groups the validation of all array-specific keywords.
-func evaluateArray(schema *Schema, data interface{}, evaluatedProps map[string]bool, evaluatedItems map[int]bool, dynamicScope *DynamicScope) ([]*EvaluationResult, []*EvaluationError) {
+func evaluateArray(schema *Schema, data interface{}, result *EvaluationResult, evaluatedProps map[string]bool, evaluatedItems map[int]bool, dynamicScope *DynamicScope) ([]*EvaluationResult, []*EvaluationError) {
 	items, ok := data.([]interface{})
 	if !ok {
 		// If data is not an array, then skip the array-specific validations.
@@ -610,9 +419,12 @@ func evaluateArray(schema *Schema, data interface{}, evaluatedProps map[string]b
 	results := []*EvaluationResult{}
 	errors := []*EvaluationError{}
 
+	applicator := schema.hasVocabulary(VocabularyApplicator)
+	validation := schema.hasVocabulary(VocabularyValidation)
+
 	// Validation keywords for applying subschemas to arrays
-	if schema.PrefixItems != nil && len(schema.PrefixItems) > 0 {
-		prefixItemsResults, prefixItemsError := evaluatePrefixItems(schema, items, evaluatedProps, evaluatedItems, dynamicScope)
+	if applicator && len(schema.PrefixItems) > 0 {
+		prefixItemsResults, prefixItemsError := evaluatePrefixItems(schema, items, result, evaluatedProps, evaluatedItems, dynamicScope)
 
 		if prefixItemsResults != nil {
 			results = append(results, prefixItemsResults...)
@@ -622,8 +434,8 @@ func evaluateArray(schema *Schema, data interface{}, evaluatedProps map[string]b
 		}
 	}
 
-	if schema.Items != nil {
-		itemsResults, itemsError := evaluateItems(schema, items, evaluatedProps, evaluatedItems, dynamicScope)
+	if !dynamicScope.stops(len(errors) > 0) && applicator && schema.Items != nil {
+		itemsResults, itemsError := evaluateItems(schema, items, result, evaluatedProps, evaluatedItems, dynamicScope)
 
 		if itemsResults != nil {
 			results = append(results, itemsResults...)
@@ -633,8 +445,8 @@ func evaluateArray(schema *Schema, data interface{}, evaluatedProps map[string]b
 		}
 	}
 
-	if schema.Contains != nil || schema.MaxContains != nil && schema.MinContains != nil {
-		containsResults, containsError := evaluateContains(schema, items, evaluatedProps, evaluatedItems, dynamicScope)
+	if !dynamicScope.stops(len(errors) > 0) && applicator && (schema.Contains != nil || schema.MaxContains != nil && schema.MinContains != nil) {
+		containsResults, containsError := evaluateContains(schema, items, result, evaluatedProps, evaluatedItems, dynamicScope)
 		if containsResults != nil {
 			results = append(results, containsResults...)
 		}
@@ -644,21 +456,21 @@ func evaluateArray(schema *Schema, data interface{}, evaluatedProps map[string]b
 	}
 
 	// Validation Keywords for Arrays
-	if schema.MaxItems != nil {
-		maxItemsError := evaluateMaxItems(schema, items)
+	if !dynamicScope.stops(len(errors) > 0) && validation && schema.MaxItems != nil {
+		maxItemsError := evaluateMaxItems(schema, len(items))
 		if maxItemsError != nil {
 			errors = append(errors, maxItemsError)
 		}
 	}
 
-	if schema.MinItems != nil {
-		minItemsError := evaluateMinItems(schema, items)
+	if !dynamicScope.stops(len(errors) > 0) && validation && schema.MinItems != nil {
+		minItemsError := evaluateMinItems(schema, len(items))
 		if minItemsError != nil {
 			errors = append(errors, minItemsError)
 		}
 	}
 
-	if schema.UniqueItems != nil && *schema.UniqueItems { // Check if UniqueItems is not nil before dereferencing
+	if !dynamicScope.stops(len(errors) > 0) && validation && schema.UniqueItems != nil && *schema.UniqueItems { // Check if UniqueItems is not nil before dereferencing
 		uniqueItemsError := evaluateUniqueItems(schema, items)
 		if uniqueItemsError != nil {
 			errors = append(errors, uniqueItemsError)
@@ -670,12 +482,43 @@ func evaluateArray(schema *Schema, data interface{}, evaluatedProps map[string]b
 
 // DynamicScope struct defines a stack specifically for handling Schema types
 type DynamicScope struct {
-	schemas []*Schema // Slice storing pointers to Schema
+	schemas          []*Schema       // Slice storing pointers to Schema
+	ctx              context.Context // Context of the validation the scope belongs to
+	instanceLocation *pathNode       // Location of the instance being evaluated
+	evaluationPath   *pathNode       // Keyword paths followed from the root schema to the schema being evaluated
+	failFast         bool            // Whether the validation stops at the first failure, see WithFailFast
+	tracking         bool            // Whether the properties and items evaluated are needed by an unevaluated* keyword
+	direction        Direction       // Direction of the data, see WithDirection
 }
 
 // NewDynamicScope creates and returns a new empty DynamicScope
 func NewDynamicScope() *DynamicScope {
-	return &DynamicScope{schemas: make([]*Schema, 0)}
+	return &DynamicScope{schemas: make([]*Schema, 0, 8), ctx: context.Background()}
+}
+
+// Context returns the context of the validation the dynamic scope belongs to
+func (ds *DynamicScope) Context() context.Context {
+	if ds.ctx == nil {
+		return context.Background()
+	}
+	return ds.ctx
+}
+
+// isCanceled reports whether the validation the dynamic scope belongs to has been canceled
+func (ds *DynamicScope) isCanceled() bool {
+	return ds.Context().Err() != nil
+}
+
+// stops reports whether the evaluation of a schema or a keyword, which failed if failed is set, can stop
+// there, which is the case in fail-fast mode
+func (ds *DynamicScope) stops(failed bool) bool {
+	return failed && ds.failFast
+}
+
+// annotates reports whether the keywords collect their annotations, which fail-fast mode skips as only
+// the validity of the instance is reported
+func (ds *DynamicScope) annotates() bool {
+	return !ds.failFast
 }
 
 // Push adds a Schema to the dynamic scope
@@ -712,6 +555,42 @@ func (ds *DynamicScope) Size() int {
 	return len(ds.schemas)
 }
 
+// InstanceLocation returns the JSON Pointer of the instance being evaluated, relative to the
+// instance the validation started with. The root instance is located by an empty string.
+func (ds *DynamicScope) InstanceLocation() string {
+	return ds.instanceLocation.String()
+}
+
+// enterInstance descends into the member or item of the current instance named by segment
+func (ds *DynamicScope) enterInstance(segment string) {
+	ds.instanceLocation = &pathNode{parent: ds.instanceLocation, segment: segment, escape: true}
+}
+
+// leaveInstance returns to the instance containing the current one
+func (ds *DynamicScope) leaveInstance() {
+	if ds.instanceLocation != nil {
+		ds.instanceLocation = ds.instanceLocation.parent
+	}
+}
+
+// EvaluationPath returns the JSON Pointer of the keywords followed from the root schema to the schema
+// being evaluated, through references. The root schema is located by an empty string.
+func (ds *DynamicScope) EvaluationPath() string {
+	return ds.evaluationPath.String()
+}
+
+// enterKeyword descends into the subschema found at the escaped keyword path of the current schema
+func (ds *DynamicScope) enterKeyword(keywordPath string) {
+	ds.evaluationPath = &pathNode{parent: ds.evaluationPath, segment: keywordPath}
+}
+
+// leaveKeyword returns to the schema containing the current one
+func (ds *DynamicScope) leaveKeyword() {
+	if ds.evaluationPath != nil {
+		ds.evaluationPath = ds.evaluationPath.parent
+	}
+}
+
 // LookupDynamicAnchor searches for a dynamic anchor in the dynamic scope
 func (ds *DynamicScope) LookupDynamicAnchor(anchor string) *Schema {
 	// use the first schema dynamic anchor matching the anchor
@@ -0,0 +1,63 @@
+package jsonschema
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"testing"
+
+	"github.com/goccy/go-json"
+)
+
+// loadExampleSchema compiles the schema of examples/jsonschema and decodes its invalid object.
+func loadExampleSchema(b *testing.B) (*Schema, map[string]interface{}) {
+	b.Helper()
+
+	schemaData, err := os.ReadFile("examples/jsonschema/schema.json")
+	if err != nil {
+		b.Fatalf("Failed to read schema: %v", err)
+	}
+	objectData, err := os.ReadFile("examples/jsonschema/object.json")
+	if err != nil {
+		b.Fatalf("Failed to read object: %v", err)
+	}
+
+	compiler := NewCompiler()
+	compiler.RegisterLoader("json-ir", func(url string) (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(schemaData)), nil
+	})
+	schema, err := compiler.GetSchema("json-ir://00000000-0000-0000-0000-000000000000@madesst/profile/finance/CORPORATE_ACCOUNT?fc2b8ca2-b6b8-43f8-a187-87b3352d5e28")
+	if err != nil {
+		b.Fatalf("Failed to compile schema: %v", err)
+	}
+
+	object := map[string]interface{}{}
+	if err := json.Unmarshal(objectData, &object); err != nil {
+		b.Fatalf("Failed to unmarshal object: %v", err)
+	}
+	return schema, object
+}
+
+func BenchmarkValidate(b *testing.B) {
+	schema, object := loadExampleSchema(b)
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		if schema.Validate(object).IsValid() {
+			b.Fatal("Expected the object to be invalid")
+		}
+	}
+}
+
+func BenchmarkValidateFast(b *testing.B) {
+	schema, object := loadExampleSchema(b)
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		if schema.ValidateFast(object).IsValid() {
+			b.Fatal("Expected the object to be invalid")
+		}
+	}
+}
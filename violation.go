@@ -0,0 +1,155 @@
+package jsonschema
+
+import (
+	"strings"
+
+	"github.com/kaptinlin/go-i18n"
+)
+
+// Violation is a keyword an instance does not conform to, located in the instance and in the schema.
+// It is meant to be mapped to the error responses of an API, such as HTTP problem details.
+type Violation struct {
+	InstancePath string                 `json:"instancePath"`     // JSON Pointer of the offending value.
+	SchemaPath   string                 `json:"schemaPath"`       // Evaluation path of the schema holding the keyword.
+	Keyword      string                 `json:"keyword"`          // Keyword that failed.
+	Code         string                 `json:"code"`             // Code identifying the failure.
+	Message      string                 `json:"message"`          // Message describing the failure.
+	Params       map[string]interface{} `json:"params,omitempty"` // Values the message is formatted with.
+	err          *EvaluationError
+}
+
+// Err returns the error the violation was built from.
+func (v Violation) Err() *EvaluationError {
+	return v.err
+}
+
+// Violations flattens the result into the errors of the innermost failing results, which locate the
+// offending keywords more precisely than the errors of the applicators containing them.
+func (e *EvaluationResult) Violations() []Violation {
+	return e.LocalizeViolations(nil)
+}
+
+// LocalizeViolations flattens the result like Violations, with messages in the language of localizer.
+func (e *EvaluationResult) LocalizeViolations(localizer *i18n.Localizer) []Violation {
+	var violations []Violation
+	walkFailures(e, func(result *EvaluationResult, err *EvaluationError) {
+		violations = append(violations, Violation{
+			InstancePath: result.InstanceLocation,
+			SchemaPath:   result.EvaluationPath,
+			Keyword:      err.keyword,
+			Code:         err.code,
+			Message:      err.Localize(localizer),
+			Params:       err.params,
+			err:          err,
+		})
+	})
+	return violations
+}
+
+// walkFailures calls fn with the errors of the innermost failing keywords of result, in order, along with
+// the result holding them. An error reporting failing subschemas, such as the one of "properties", is
+// replaced by the errors of the results of these subschemas.
+func walkFailures(result *EvaluationResult, fn func(result *EvaluationResult, err *EvaluationError)) {
+	if result == nil || result.IsValid() {
+		return
+	}
+
+	for _, err := range result.errorList {
+		explained := false
+		if keyword := subschemasKeyword(err); keyword != "" && result.schema != nil {
+			for _, detail := range result.Details {
+				if !detail.IsValid() && result.schema.detailKeyword(detail.schema) == keyword {
+					explained = true
+					walkFailures(detail, fn)
+				}
+			}
+		}
+		if !explained {
+			fn(result, err)
+		}
+	}
+}
+
+// subschemasKeyword returns the keyword whose failing subschemas err reports, if any. The failures of
+// "oneOf" matching several subschemas and of "not" are not explained by failing subschemas.
+func subschemasKeyword(err *EvaluationError) string {
+	switch err.code {
+	case "pattern_property_mismatch", "pattern_properties_mismatch":
+		return "patternProperties"
+	case "unevaluated_property_mismatch", "unevaluated_properties_mismatch":
+		return "unevaluatedProperties"
+	case "one_of_multiple_matches", "not_schema_mismatch":
+		return ""
+	}
+	return err.keyword
+}
+
+// detailKeyword returns the keyword of s holding subschema, whose result is a detail of the result of s.
+func (s *Schema) detailKeyword(subschema *Schema) string {
+	keyword, _ := s.detailPath(subschema)
+	return keyword
+}
+
+// detailPath returns the keyword of s holding subschema, whose result is a detail of the result of s,
+// along with the JSON Pointer of subschema relative to s. Subschemas found through a reference resolved
+// during the evaluation belong to "$ref" or "$dynamicRef".
+func (s *Schema) detailPath(subschema *Schema) (string, string) {
+	if subschema == s.ResolvedRef {
+		return "$ref", "/$ref"
+	}
+	for _, sub := range s.subschemas() {
+		if sub.schema != subschema || sub.segments[0] == "$defs" {
+			continue
+		}
+		path := ""
+		for _, segment := range sub.segments {
+			path += "/" + escapeJSONPointerSegment(segment)
+		}
+		return sub.segments[0], path
+	}
+	if strings.HasPrefix(s.Ref, "tf://") {
+		return "$ref", "/$ref"
+	}
+	return "$dynamicRef", "/$dynamicRef"
+}
+
+// Err returns nil if the instance is valid, and otherwise a *ValidationError holding the violations of
+// the result.
+func (e *EvaluationResult) Err() error {
+	if e.IsValid() {
+		return nil
+	}
+	return &ValidationError{Violations: e.Violations()}
+}
+
+// ValidationError reports the violations of an instance that does not conform to a schema. It unwraps to
+// ErrInvalidInstance and to the error of each violation, so that they can be matched with errors.Is and
+// errors.As.
+type ValidationError struct {
+	Violations []Violation
+}
+
+// Error returns a description of the keywords the instance does not conform to.
+func (e *ValidationError) Error() string {
+	messages := make([]string, len(e.Violations))
+	for i, violation := range e.Violations {
+		location := violation.InstancePath
+		if location == "" {
+			location = "/"
+		}
+		messages[i] = location + ": " + violation.Message
+	}
+	return ErrInvalidInstance.Error() + ": " + strings.Join(messages, "; ")
+}
+
+// Unwrap returns ErrInvalidInstance and the errors of the violations.
+func (e *ValidationError) Unwrap() []error {
+	errs := make([]error, 0, len(e.Violations)+1)
+	errs = append(errs, ErrInvalidInstance)
+	for _, violation := range e.Violations {
+		if violation.err != nil {
+			errs = append(errs, violation.err)
+		}
+	}
+	return errs
+}
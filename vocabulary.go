@@ -0,0 +1,99 @@
+package jsonschema
+
+import (
+	"context"
+	"fmt"
+)
+
+// The vocabularies defined by JSON Schema 2020-12.
+//
+// Reference: https://json-schema.org/draft/2020-12/json-schema-core#name-json-schema-core-vocabulary
+const (
+	VocabularyCore             = "https://json-schema.org/draft/2020-12/vocab/core"
+	VocabularyApplicator       = "https://json-schema.org/draft/2020-12/vocab/applicator"
+	VocabularyUnevaluated      = "https://json-schema.org/draft/2020-12/vocab/unevaluated"
+	VocabularyValidation       = "https://json-schema.org/draft/2020-12/vocab/validation"
+	VocabularyMetaData         = "https://json-schema.org/draft/2020-12/vocab/meta-data"
+	VocabularyFormatAnnotation = "https://json-schema.org/draft/2020-12/vocab/format-annotation"
+	VocabularyFormatAssertion  = "https://json-schema.org/draft/2020-12/vocab/format-assertion"
+	VocabularyContent          = "https://json-schema.org/draft/2020-12/vocab/content"
+)
+
+// standardVocabularies lists the vocabularies every Compiler understands.
+var standardVocabularies = []string{
+	VocabularyCore,
+	VocabularyApplicator,
+	VocabularyUnevaluated,
+	VocabularyValidation,
+	VocabularyMetaData,
+	VocabularyFormatAnnotation,
+	VocabularyFormatAssertion,
+	VocabularyContent,
+}
+
+// RegisterVocabulary declares a custom vocabulary as supported, so that meta-schemas requiring it
+// in their $vocabulary can be used. The keywords of the vocabulary are handled by the application.
+func (c *Compiler) RegisterVocabulary(uri string) *Compiler {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.vocabularies[uri] = true
+	return c
+}
+
+// supportsVocabulary tells whether the compiler understands the given vocabulary.
+func (c *Compiler) supportsVocabulary(uri string) bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.vocabularies[uri]
+}
+
+// resolveVocabularies determines the vocabularies active in a schema document from the $vocabulary
+// keyword of the meta-schema named by its $schema. A nil set stands for the default 2020-12 dialect.
+// Unsupported vocabularies are skipped when optional and fail with ErrUnsupportedVocabulary when
+// required. Every other meta-schema is loaded, the official ones embedded in the package, such as
+// the vocabulary meta-schemas of 2020-12, from their copies, and the meta-schemas without $vocabulary,
+// such as the ones of earlier drafts, leave the default dialect. A meta-schema that cannot be loaded
+// is reported as an unresolved reference, and the document is then evaluated with the default dialect.
+func (c *Compiler) resolveVocabularies(ctx context.Context, schema *Schema) (map[string]bool, *ReferenceError, error) {
+	id, _ := splitRef(schema.Schema)
+	if id == "" || id == MetaSchemaURL {
+		return nil, nil, nil
+	}
+
+	var vocabulary map[string]bool
+	if selfID, _ := splitRef(schema.ID); id == selfID {
+		// A meta-schema describing itself declares its own vocabularies.
+		vocabulary = schema.Vocabulary
+	} else {
+		metaSchema, err := c.GetSchemaContext(ctx, id)
+		if err != nil {
+			return nil, &ReferenceError{Keyword: "$schema", Ref: schema.Schema, URI: id, Location: "/$schema", Err: err}, nil
+		}
+		vocabulary = metaSchema.Vocabulary
+	}
+
+	if vocabulary == nil {
+		return nil, nil, nil
+	}
+
+	active := make(map[string]bool, len(vocabulary))
+	for uri, required := range vocabulary {
+		if c.supportsVocabulary(uri) {
+			active[uri] = true
+		} else if required {
+			return nil, nil, fmt.Errorf("%w: %s", ErrUnsupportedVocabulary, uri)
+		}
+	}
+	return active, nil, nil
+}
+
+// hasVocabulary tells whether the keywords of the given vocabulary apply to s. Schemas written in
+// the default dialect use every standard vocabulary except format-assertion.
+func (s *Schema) hasVocabulary(uri string) bool {
+	if s.vocabularies == nil {
+		return uri != VocabularyFormatAssertion
+	}
+	return s.vocabularies[uri]
+}
@@ -0,0 +1,126 @@
+package jsonschema
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/test-go/testify/assert"
+)
+
+// compileMetaSchema registers a meta-schema declaring the given vocabularies with the compiler.
+func compileMetaSchema(t *testing.T, compiler *Compiler, id string, vocabulary string) {
+	t.Helper()
+
+	_, err := compiler.Compile([]byte(`{
+		"$schema": "https://json-schema.org/draft/2020-12/schema",
+		"$id": "` + id + `",
+		"$vocabulary": ` + vocabulary + `
+	}`))
+	assert.NoError(t, err)
+}
+
+func TestVocabularies(t *testing.T) {
+	compiler := NewCompiler()
+	compileMetaSchema(t, compiler, "http://example.com/meta/no-validation", `{
+		"https://json-schema.org/draft/2020-12/vocab/core": true,
+		"https://json-schema.org/draft/2020-12/vocab/applicator": true
+	}`)
+	compileMetaSchema(t, compiler, "http://example.com/meta/format-assertion", `{
+		"https://json-schema.org/draft/2020-12/vocab/core": true,
+		"https://json-schema.org/draft/2020-12/vocab/format-assertion": false
+	}`)
+	compileMetaSchema(t, compiler, "http://example.com/meta/custom", `{
+		"https://json-schema.org/draft/2020-12/vocab/core": true,
+		"https://json-schema.org/draft/2020-12/vocab/validation": true,
+		"http://example.com/vocab/optional": false,
+		"http://example.com/vocab/x-tf": true
+	}`)
+
+	t.Run("disabled validation vocabulary", func(t *testing.T) {
+		schema, err := compiler.Compile([]byte(`{
+			"$schema": "http://example.com/meta/no-validation",
+			"properties": {"n": {"minimum": 10}, "bad": false}
+		}`))
+		assert.NoError(t, err)
+		assert.True(t, schema.Validate(map[string]interface{}{"n": 1}).IsValid())
+		assert.False(t, schema.Validate(map[string]interface{}{"bad": 1}).IsValid())
+	})
+
+	t.Run("format-assertion vocabulary", func(t *testing.T) {
+		schema, err := compiler.Compile([]byte(`{"$schema": "http://example.com/meta/format-assertion", "format": "ipv4"}`))
+		assert.NoError(t, err)
+		assert.True(t, schema.Validate("127.0.0.1").IsValid())
+		assert.False(t, schema.Validate("not-an-ipv4").IsValid())
+
+		schema, err = compiler.Compile([]byte(`{"format": "ipv4"}`))
+		assert.NoError(t, err)
+		assert.True(t, schema.Validate("not-an-ipv4").IsValid(), "Expected the default dialect to only annotate formats")
+	})
+
+	t.Run("unsupported required vocabulary", func(t *testing.T) {
+		_, err := compiler.Compile([]byte(`{"$schema": "http://example.com/meta/custom", "type": "number"}`))
+		assert.True(t, errors.Is(err, ErrUnsupportedVocabulary), "Expected ErrUnsupportedVocabulary, got %v", err)
+		assert.Contains(t, err.Error(), "http://example.com/vocab/x-tf")
+	})
+
+	t.Run("registered vocabulary", func(t *testing.T) {
+		compiler.RegisterVocabulary("http://example.com/vocab/x-tf")
+
+		schema, err := compiler.Compile([]byte(`{"$schema": "http://example.com/meta/custom", "type": "number"}`))
+		assert.NoError(t, err)
+		assert.True(t, schema.Validate(20).IsValid())
+		assert.False(t, schema.Validate("foobar").IsValid())
+	})
+
+	t.Run("unresolvable meta-schema", func(t *testing.T) {
+		schemaJSON := []byte(`{"$schema": "unknown://example.com/meta", "type": "number"}`)
+
+		schema, err := compiler.Compile(schemaJSON)
+		assert.NoError(t, err)
+		if assert.Len(t, schema.CompileWarnings(), 1) {
+			assert.Equal(t, "$schema", schema.CompileWarnings()[0].Keyword)
+		}
+		assert.False(t, schema.Validate("foobar").IsValid())
+
+		_, err = NewCompiler().SetStrictRefs(true).Compile(schemaJSON)
+		assert.True(t, errors.Is(err, ErrNoLoaderRegistered), "Expected the meta-schema lookup to fail, got %v", err)
+	})
+
+	t.Run("embedded vocabulary meta-schema", func(t *testing.T) {
+		schema, err := NewCompiler().SetStrictRefs(true).Compile([]byte(`{
+			"$schema": "https://json-schema.org/draft/2020-12/meta/validation",
+			"properties": {"n": {"minimum": 10}},
+			"minimum": 5
+		}`))
+		assert.NoError(t, err)
+		assert.True(t, schema.Validate(map[string]interface{}{"n": 1}).IsValid(), "Expected the applicator vocabulary to be inactive")
+		assert.False(t, schema.Validate(1).IsValid())
+	})
+
+	t.Run("official-looking meta-schema", func(t *testing.T) {
+		compileMetaSchema(t, compiler, "http://example.com/json-schema.org/meta", `{
+			"https://json-schema.org/draft/2020-12/vocab/core": true,
+			"http://example.com/vocab/unknown": true
+		}`)
+
+		_, err := compiler.Compile([]byte(`{"$schema": "http://example.com/json-schema.org/meta", "type": "number"}`))
+		assert.True(t, errors.Is(err, ErrUnsupportedVocabulary), "Expected ErrUnsupportedVocabulary, got %v", err)
+	})
+
+	t.Run("earlier draft", func(t *testing.T) {
+		compiler := NewCompiler().SetStrictRefs(true).RegisterLoader("http", func(url string) (io.ReadCloser, error) {
+			return io.NopCloser(strings.NewReader(`{
+				"$schema": "http://json-schema.org/draft-07/schema#",
+				"$id": "http://json-schema.org/draft-07/schema#",
+				"type": ["object", "boolean"]
+			}`)), nil
+		})
+
+		schema, err := compiler.Compile([]byte(`{"$schema": "http://json-schema.org/draft-07/schema#", "type": "number"}`))
+		assert.NoError(t, err)
+		assert.True(t, schema.Validate(20).IsValid())
+		assert.False(t, schema.Validate("foobar").IsValid())
+	})
+}
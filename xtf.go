@@ -0,0 +1,131 @@
+package jsonschema
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/goccy/go-json"
+)
+
+// FacetsKeyword is the "x-tf-facets" keyword, which names the facets of a form a schema belongs to,
+// such as ["BASIC_INFO"]. It annotates the instances the schema validates with the list of its facets,
+// which AnnotationsAt and the output formats report, and sets the deprecated XTFFacets of the result.
+// NewCompiler registers it.
+type FacetsKeyword struct{}
+
+// Compile parses the list of facet names.
+func (FacetsKeyword) Compile(schema *Schema, value json.RawMessage) (KeywordEvaluator, error) {
+	var facets []string
+	if err := json.Unmarshal(value, &facets); err != nil {
+		return nil, fmt.Errorf("expected an array of strings: %w", err)
+	}
+	return facetsEvaluator(facets), nil
+}
+
+type facetsEvaluator []string
+
+func (facets facetsEvaluator) Evaluate(evaluation *KeywordEvaluation) {
+	if len(facets) == 0 {
+		return
+	}
+
+	evaluation.result.XTFFacets = facets
+	if evaluation.DynamicScope.annotates() {
+		evaluation.AddAnnotation([]string(facets))
+	}
+}
+
+// AcceptedObjectsKeyword is the "x-tf-accepted-objects" keyword, which restricts the objects a string
+// instance may refer to. The instance is the URI of an object, which is loaded with the loader the
+// compiler registered for its scheme. The object is accepted if it matches one of the values of the
+// keyword: an object value matches when each of its members equals the member of the same name of the
+// referenced object, such as {"@archetype": "form", "@kind": "PERSONAL_DETAILS"}, and a string value
+// matches when the "@schema" member of the referenced object starts with it, with or without a version.
+// NewCompiler registers it.
+type AcceptedObjectsKeyword struct{}
+
+// Compile parses the list of accepted objects.
+func (AcceptedObjectsKeyword) Compile(schema *Schema, value json.RawMessage) (KeywordEvaluator, error) {
+	var accepted []interface{}
+	if err := json.Unmarshal(value, &accepted); err != nil {
+		return nil, fmt.Errorf("expected an array: %w", err)
+	}
+	for _, object := range accepted {
+		switch object.(type) {
+		case string, map[string]interface{}:
+		default:
+			return nil, fmt.Errorf("expected an array of strings and objects, got %v", object)
+		}
+	}
+	return acceptedObjectsEvaluator(accepted), nil
+}
+
+type acceptedObjectsEvaluator []interface{}
+
+func (accepted acceptedObjectsEvaluator) Evaluate(evaluation *KeywordEvaluation) {
+	uri, ok := evaluation.Instance.(string)
+	if !ok {
+		return // Only references to objects are checked.
+	}
+
+	object, err := loadObject(evaluation, uri)
+	if err != nil {
+		evaluation.AddError(NewEvaluationError(evaluation.Keyword, "id_cant_reach", "Cant reach the referenced object {id}", map[string]interface{}{
+			"id": uri,
+		}))
+		return
+	}
+
+	for _, acceptedObject := range accepted {
+		if acceptsObject(acceptedObject, object) {
+			return
+		}
+	}
+	evaluation.AddError(NewEvaluationError(evaluation.Keyword, "id_forbidden_schema", "Referenced object {id} is not of an accepted kind", map[string]interface{}{
+		"id": uri,
+	}))
+}
+
+// loadObject loads the object found at uri with the loader registered for its scheme.
+func loadObject(evaluation *KeywordEvaluation, uri string) (map[string]interface{}, error) {
+	loader, ok := evaluation.Schema.compiler.getLoader(getURLScheme(uri))
+	if !ok {
+		return nil, ErrNoLoaderRegistered
+	}
+
+	body, err := loader(evaluation.DynamicScope.Context(), uri)
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close() //nolint:errcheck
+
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return nil, err
+	}
+
+	var object map[string]interface{}
+	if err := json.Unmarshal(data, &object); err != nil {
+		return nil, err
+	}
+	return object, nil
+}
+
+// acceptsObject reports whether the value of an "x-tf-accepted-objects" item accepts object.
+func acceptsObject(accepted interface{}, object map[string]interface{}) bool {
+	switch accepted := accepted.(type) {
+	case string:
+		schema, ok := object["@schema"].(string)
+		return ok && strings.HasPrefix(schema, accepted)
+	case map[string]interface{}:
+		for name, value := range accepted {
+			member, exists := object[name]
+			if !exists || !equalJSON(member, value) {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}